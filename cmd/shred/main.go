@@ -6,9 +6,14 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/divin3circle/shred/internal/app"
+	"github.com/divin3circle/shred/internal/cli"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	p := tea.NewProgram(app.NewModel(), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
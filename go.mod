@@ -11,6 +11,8 @@ require (
 	github.com/mdp/qrterminal/v3 v3.2.1
 	github.com/tyler-smith/go-bip39 v1.1.0
 	golang.org/x/crypto v0.45.0
+	golang.org/x/term v0.37.0
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
@@ -42,10 +44,8 @@ require (
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
 	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
-	golang.org/x/term v0.37.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b // indirect
 	google.golang.org/grpc v1.76.0 // indirect
-	google.golang.org/protobuf v1.36.10 // indirect
 	rsc.io/qr v0.2.0 // indirect
 )
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Client talks to a running agent. It is safe for concurrent use; requests
+// on one connection are answered in order.
+type Client struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	encoder *json.Encoder
+	nextID  int64
+}
+
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to agent at %s: %w", path, err)
+	}
+	return &Client{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		encoder: json.NewEncoder(conn),
+	}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) Info() (Info, error) {
+	resp, err := c.call(Request{Method: MethodInfo})
+	if err != nil {
+		return Info{}, err
+	}
+	if resp.Info == nil {
+		return Info{}, errors.New("agent returned no account information")
+	}
+	return *resp.Info, nil
+}
+
+// Sign sends frozen transaction bytes to the agent and returns them with the
+// agent's signature added. It blocks until the request is approved or denied.
+func (c *Client) Sign(transaction []byte) ([]byte, error) {
+	resp, err := c.call(Request{
+		Method:      MethodSign,
+		Transaction: base64.StdEncoding.EncodeToString(transaction),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Transaction)
+}
+
+func (c *Client) call(req Request) (Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	req.ID = c.nextID
+	if err := c.encoder.Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send request to agent: %w", err)
+	}
+
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read agent response: %w", err)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return Response{}, fmt.Errorf("invalid agent response: %w", err)
+	}
+	if resp.ID != req.ID {
+		return Response{}, errors.New("agent response does not match the request")
+	}
+	if resp.Error != "" {
+		return Response{}, errors.New(resp.Error)
+	}
+	return resp, nil
+}
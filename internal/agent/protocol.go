@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/divin3circle/shred/internal/crypto"
+)
+
+// The agent speaks newline-delimited JSON over a Unix domain socket. Each
+// line from the client is a Request; the agent answers every request with
+// one Response carrying the same ID. Transactions are frozen Hedera
+// transaction bytes, base64 encoded.
+const (
+	MethodInfo = "info"
+	MethodSign = "sign"
+)
+
+// SocketEnv overrides the default socket path, like SSH_AUTH_SOCK.
+const SocketEnv = "SHRED_AGENT_SOCK"
+
+var (
+	ErrDenied   = errors.New("signing request denied")
+	ErrLocked   = errors.New("agent is locked")
+	ErrTimeout  = errors.New("signing request timed out waiting for approval")
+	ErrShutdown = errors.New("agent is shutting down")
+)
+
+type Request struct {
+	ID          int64  `json:"id"`
+	Method      string `json:"method"`
+	Transaction string `json:"transaction,omitempty"`
+}
+
+type Response struct {
+	ID          int64  `json:"id"`
+	Error       string `json:"error,omitempty"`
+	Transaction string `json:"transaction,omitempty"`
+	Info        *Info  `json:"info,omitempty"`
+}
+
+// Info describes the account the agent signs for and the highest fee it
+// signs.
+type Info struct {
+	AccountID      string         `json:"account_id,omitempty"`
+	PublicKey      string         `json:"public_key"`
+	KeyType        crypto.KeyType `json:"key_type"`
+	Network        string         `json:"network"`
+	NodeAddress    string         `json:"node_address,omitempty"`
+	NodeAccountID  string         `json:"node_account_id,omitempty"`
+	MirrorURL      string         `json:"mirror_url,omitempty"`
+	MaxFeeTinybars int64          `json:"max_fee_tinybars"`
+}
+
+func DefaultSocketPath() (string, error) {
+	if path := os.Getenv(SocketEnv); path != "" {
+		return path, nil
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		return filepath.Join(runtimeDir, "shred", "agent.sock"), nil
+	}
+
+	walletDir, err := crypto.GetWalletDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(walletDir, "agent.sock"), nil
+}
@@ -0,0 +1,136 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// Summary is what the wallet shows, and the auto-approve rules check, before
+// a transaction is signed.
+type Summary struct {
+	Type           string          `json:"type"`
+	TransactionID  string          `json:"transaction_id"`
+	Payer          string          `json:"payer"`
+	Memo           string          `json:"memo"`
+	MaxFee         string          `json:"max_fee"`
+	ValidStart     time.Time       `json:"valid_start"`
+	ValidUntil     time.Time       `json:"valid_until"`
+	HbarTransfers  []Transfer      `json:"hbar_transfers,omitempty"`
+	TokenTransfers []TokenTransfer `json:"token_transfers,omitempty"`
+	NFTTransfers   int             `json:"nft_transfers,omitempty"`
+}
+
+type Transfer struct {
+	Account  string `json:"account"`
+	Tinybars int64  `json:"tinybars"`
+}
+
+type TokenTransfer struct {
+	TokenID string `json:"token_id"`
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
+}
+
+func Summarize(tx sdk.TransactionInterface) Summary {
+	var summary Summary
+
+	if id, err := sdk.TransactionGetTransactionID(tx); err == nil {
+		summary.TransactionID = id.String()
+		if id.AccountID != nil {
+			summary.Payer = id.AccountID.String()
+		}
+		if id.ValidStart != nil {
+			summary.ValidStart = id.ValidStart.UTC()
+			if duration, err := sdk.TransactionGetTransactionValidDuration(tx); err == nil {
+				summary.ValidUntil = summary.ValidStart.Add(duration)
+			}
+		}
+	}
+	if memo, err := sdk.TransactionGetTransactionMemo(tx); err == nil {
+		summary.Memo = memo
+	}
+	if fee, err := sdk.TransactionGetMaxTransactionFee(tx); err == nil {
+		summary.MaxFee = fee.String()
+	}
+
+	switch t := tx.(type) {
+	case sdk.TransferTransaction:
+		summary.Type = "CryptoTransfer"
+		for account, amount := range t.GetHbarTransfers() {
+			summary.HbarTransfers = append(summary.HbarTransfers, Transfer{Account: account.String(), Tinybars: amount.AsTinybar()})
+		}
+		for token, transfers := range t.GetTokenTransfers() {
+			for _, transfer := range transfers {
+				summary.TokenTransfers = append(summary.TokenTransfers, TokenTransfer{
+					TokenID: token.String(),
+					Account: transfer.AccountID.String(),
+					Amount:  transfer.Amount,
+				})
+			}
+		}
+		for _, transfers := range t.GetNftTransfers() {
+			summary.NFTTransfers += len(transfers)
+		}
+		sort.Slice(summary.HbarTransfers, func(i, j int) bool {
+			return summary.HbarTransfers[i].Tinybars < summary.HbarTransfers[j].Tinybars
+		})
+	default:
+		summary.Type = strings.TrimSuffix(strings.TrimPrefix(fmt.Sprintf("%T", tx), "hiero."), "Transaction")
+	}
+	return summary
+}
+
+// AutoApproved reports whether a rule allows signing without confirmation.
+// Only plain HBAR transfers paid by and debiting accountID qualify.
+func (s Summary) AutoApproved(rules []crypto.AgentRule, accountID string) bool {
+	if s.Type != "CryptoTransfer" || accountID == "" || s.Payer != accountID {
+		return false
+	}
+	if len(s.TokenTransfers) > 0 || s.NFTTransfers > 0 {
+		return false
+	}
+
+	var spent int64
+	var recipients []string
+	for _, transfer := range s.HbarTransfers {
+		switch {
+		case transfer.Tinybars < 0 && transfer.Account != accountID:
+			return false
+		case transfer.Tinybars < 0:
+			spent -= transfer.Tinybars
+		case transfer.Tinybars > 0 && transfer.Account != accountID:
+			recipients = append(recipients, transfer.Account)
+		}
+	}
+
+	for _, rule := range rules {
+		if spent <= rule.MaxTinybars && allowedRecipients(rule.Recipients, recipients) {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedRecipients(allowed, recipients []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, recipient := range recipients {
+		found := false
+		for _, account := range allowed {
+			if account == recipient {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+func TestSummarize(t *testing.T) {
+	call := testSignCall(t, "0.0.1001", 250, "0.0.2002")
+	summary := call.Summary
+
+	if summary.Type != "CryptoTransfer" || summary.Payer != "0.0.1001" {
+		t.Errorf("type %q, payer %q", summary.Type, summary.Payer)
+	}
+	want := []Transfer{{Account: "0.0.1001", Tinybars: -250}, {Account: "0.0.2002", Tinybars: 250}}
+	if len(summary.HbarTransfers) != len(want) {
+		t.Fatalf("transfers = %+v, want %+v", summary.HbarTransfers, want)
+	}
+	for i := range want {
+		if summary.HbarTransfers[i] != want[i] {
+			t.Errorf("transfer %d = %+v, want %+v", i, summary.HbarTransfers[i], want[i])
+		}
+	}
+	if summary.ValidUntil.Sub(summary.ValidStart) != 120*time.Second {
+		t.Errorf("valid from %s until %s", summary.ValidStart, summary.ValidUntil)
+	}
+}
+
+func TestAutoApproved(t *testing.T) {
+	const account = "0.0.1001"
+	hbar := func(account string, tinybars int64) Transfer {
+		return Transfer{Account: account, Tinybars: tinybars}
+	}
+	payment := Summary{
+		Type:          "CryptoTransfer",
+		Payer:         account,
+		HbarTransfers: []Transfer{hbar(account, -500), hbar("0.0.2002", 500)},
+	}
+
+	tests := []struct {
+		name    string
+		rules   []crypto.AgentRule
+		summary func(Summary) Summary
+		// unverified leaves the agent without a known account ID.
+		unverified bool
+		want       bool
+	}{
+		{name: "no rules", want: false},
+		{name: "within a rule for any recipient", rules: []crypto.AgentRule{{MaxTinybars: 500}}, want: true},
+		{name: "above the limit", rules: []crypto.AgentRule{{MaxTinybars: 499}}, want: false},
+		{
+			name:  "listed recipient",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000, Recipients: []string{"0.0.3003", "0.0.2002"}}},
+			want:  true,
+		},
+		{
+			name:  "unlisted recipient",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000, Recipients: []string{"0.0.3003"}}},
+			want:  false,
+		},
+		{
+			name:  "a second rule applies",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000, Recipients: []string{"0.0.3003"}}, {MaxTinybars: 500}},
+			want:  true,
+		},
+		{
+			name:       "unverified account",
+			rules:      []crypto.AgentRule{{MaxTinybars: 1_000}},
+			unverified: true,
+			want:       false,
+		},
+		{
+			name:  "paid by another account",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000}},
+			summary: func(s Summary) Summary {
+				s.Payer = "0.0.3003"
+				return s
+			},
+			want: false,
+		},
+		{
+			name:  "debits another account",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000}},
+			summary: func(s Summary) Summary {
+				s.HbarTransfers = []Transfer{hbar(account, -500), hbar("0.0.3003", -500), hbar("0.0.2002", 1_000)}
+				return s
+			},
+			want: false,
+		},
+		{
+			name:  "token transfer",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000}},
+			summary: func(s Summary) Summary {
+				s.TokenTransfers = []TokenTransfer{{TokenID: "0.0.5005", Account: "0.0.2002", Amount: 1}}
+				return s
+			},
+			want: false,
+		},
+		{
+			name:  "NFT transfer",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000}},
+			summary: func(s Summary) Summary {
+				s.NFTTransfers = 1
+				return s
+			},
+			want: false,
+		},
+		{
+			name:  "another transaction type",
+			rules: []crypto.AgentRule{{MaxTinybars: 1_000}},
+			summary: func(s Summary) Summary {
+				s.Type = "TokenAssociate"
+				return s
+			},
+			want: false,
+		},
+	}
+
+	for _, tc := range tests {
+		summary := payment
+		if tc.summary != nil {
+			summary = tc.summary(summary)
+		}
+		accountID := account
+		if tc.unverified {
+			accountID = ""
+		}
+		if got := summary.AutoApproved(tc.rules, accountID); got != tc.want {
+			t.Errorf("%s: AutoApproved = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAutoApprovedDecodedTransfer(t *testing.T) {
+	rules := []crypto.AgentRule{{MaxTinybars: sdk.HbarFrom(1, sdk.HbarUnits.Hbar).AsTinybar(), Recipients: []string{"0.0.2002"}}}
+	if call := testSignCall(t, "0.0.1001", 100_000_000, "0.0.2002"); !call.Summary.AutoApproved(rules, "0.0.1001") {
+		t.Error("a 1 HBAR payment to a listed recipient needs approval")
+	}
+	// Half an HBAR to each recipient stays within the limit.
+	if call := testSignCall(t, "0.0.1001", 50_000_000, "0.0.2002", "0.0.3003"); call.Summary.AutoApproved(rules, "0.0.1001") {
+		t.Error("a payment that includes an unlisted recipient was auto-approved")
+	}
+}
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+const (
+	ApprovalTimeout = 2 * time.Minute
+	maxRequestSize  = 1 << 20
+)
+
+// Call is a request waiting for the wallet to answer it. Sign calls carry the
+// decoded transaction and its summary.
+type Call struct {
+	Request     Request
+	Transaction sdk.TransactionInterface
+	Summary     Summary
+	Received    time.Time
+
+	reply chan Response
+}
+
+// Reply answers the call. Only the first reply is delivered.
+func (c *Call) Reply(resp Response) {
+	resp.ID = c.Request.ID
+	select {
+	case c.reply <- resp:
+	default:
+	}
+}
+
+func (c *Call) Fail(err error) {
+	c.Reply(Response{Error: err.Error()})
+}
+
+// Server accepts agent connections and hands each request to the wallet
+// through Calls. It never holds key material itself.
+type Server struct {
+	path     string
+	listener net.Listener
+	calls    chan *Call
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Listen creates the socket at path, readable only by the current user. A
+// stale socket left by an agent that exited uncleanly is replaced.
+func Listen(path string) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("an agent is already listening on %s", path)
+		}
+		os.Remove(path)
+	}
+
+	// The socket is created in a private directory and only moved into place
+	// once its permissions are restricted, since it is created with the
+	// process umask.
+	private, err := os.MkdirTemp(filepath.Dir(path), ".agent-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	defer os.Remove(private)
+
+	privatePath := filepath.Join(private, "s")
+	listener, err := net.Listen("unix", privatePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(privatePath, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(privatePath, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return &Server{
+		path:     path,
+		listener: listener,
+		calls:    make(chan *Call),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (s *Server) Path() string {
+	return s.path
+}
+
+func (s *Server) Calls() <-chan *Call {
+	return s.calls
+}
+
+func (s *Server) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	err := s.listener.Close()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestSize)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			encoder.Encode(Response{Error: "invalid request: " + err.Error()})
+			continue
+		}
+
+		resp := s.dispatch(req)
+		resp.ID = req.ID
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(req Request) Response {
+	call := &Call{
+		Request:  req,
+		Received: time.Now(),
+		reply:    make(chan Response, 1),
+	}
+
+	switch req.Method {
+	case MethodInfo:
+	case MethodSign:
+		data, err := base64.StdEncoding.DecodeString(req.Transaction)
+		if err != nil {
+			return Response{Error: "transaction is not valid base64"}
+		}
+		tx, err := sdk.TransactionFromBytes(data)
+		if err != nil {
+			return Response{Error: "failed to decode transaction: " + err.Error()}
+		}
+		call.Transaction = tx
+		call.Summary = Summarize(tx)
+	default:
+		return Response{Error: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+
+	timeout := time.NewTimer(ApprovalTimeout)
+	defer timeout.Stop()
+
+	select {
+	case s.calls <- call:
+	case <-s.done:
+		return Response{Error: ErrShutdown.Error()}
+	case <-timeout.C:
+		return Response{Error: ErrTimeout.Error()}
+	}
+
+	select {
+	case resp := <-call.reply:
+		return resp
+	case <-s.done:
+		return Response{Error: ErrShutdown.Error()}
+	case <-timeout.C:
+		return Response{Error: ErrTimeout.Error()}
+	}
+}
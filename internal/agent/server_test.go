@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// testServer listens in a temporary directory until the test ends. Tests
+// take calls from it themselves, so the session is only used by the test's
+// goroutine, as it is by the agent's screen.
+func testServer(t *testing.T) *Server {
+	t.Helper()
+	server, err := Listen(filepath.Join(t.TempDir(), "agent.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+func TestListenSocketIsPrivate(t *testing.T) {
+	server := testServer(t)
+
+	info, err := os.Stat(server.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("socket permissions = %o, want no access for group or others", perm)
+	}
+
+	if _, err := Listen(server.Path()); err == nil {
+		t.Error("a second agent listened on a socket in use")
+	}
+}
+
+func TestClientInfoAndSign(t *testing.T) {
+	session, _ := testSession(t, nil)
+	defer session.Close()
+	server := testServer(t)
+
+	client, err := Dial(server.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	infos := make(chan Info, 1)
+	go func() {
+		info, err := client.Info()
+		if err != nil {
+			t.Error(err)
+		}
+		infos <- info
+	}()
+	session.Handle(<-server.Calls())
+	if info := <-infos; info.AccountID != "0.0.1001" || info.PublicKey != session.PublicKey.StringRaw() ||
+		info.MaxFeeTinybars != session.Metadata.MaxFee().AsTinybar() {
+		t.Errorf("info = %+v", info)
+	}
+
+	unsigned, err := sdk.TransactionToBytes(testSignCall(t, "0.0.1001", 1, "0.0.2002").Transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := make(chan []byte, 1)
+	go func() {
+		data, err := client.Sign(unsigned)
+		if err != nil {
+			t.Error(err)
+		}
+		signed <- data
+	}()
+
+	// Without auto-approve rules the request waits for the user.
+	session.Handle(<-server.Calls())
+	if len(session.Pending()) != 1 {
+		t.Fatalf("%d calls pending, want 1", len(session.Pending()))
+	}
+	session.Approve(time.Now())
+
+	tx, err := sdk.TransactionFromBytes(<-signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatures, err := sdk.TransactionGetSignatures(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, bySigner := range signatures {
+		for key := range bySigner {
+			found = found || key.StringRaw() == session.PublicKey.StringRaw()
+		}
+	}
+	if !found {
+		t.Error("the returned transaction does not carry the agent's signature")
+	}
+}
+
+func TestClientSignDenied(t *testing.T) {
+	session, _ := testSession(t, nil)
+	defer session.Close()
+	server := testServer(t)
+
+	client, err := Dial(server.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	unsigned, err := sdk.TransactionToBytes(testSignCall(t, "0.0.1001", 1, "0.0.2002").Transaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Sign(unsigned)
+		errs <- err
+	}()
+	session.Handle(<-server.Calls())
+	session.Deny()
+	if err := <-errs; err == nil || err.Error() != ErrDenied.Error() {
+		t.Errorf("denied request: err = %v, want %v", err, ErrDenied)
+	}
+}
+
+// The server answers malformed requests itself, one response per line with
+// the request's ID, and keeps the connection open.
+func TestServerRejectsInvalidRequests(t *testing.T) {
+	server := testServer(t)
+
+	conn, err := net.Dial("unix", server.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	tests := []struct {
+		request string
+		id      int64
+		err     string
+	}{
+		{`not json`, 0, "invalid request"},
+		{`{"id": 7, "method": "export_key"}`, 7, `unknown method "export_key"`},
+		{`{"id": 8, "method": "sign", "transaction": "%%%"}`, 8, "transaction is not valid base64"},
+		{`{"id": 9, "method": "sign", "transaction": "AAEC"}`, 9, "failed to decode transaction"},
+	}
+	for _, tc := range tests {
+		if _, err := conn.Write([]byte(tc.request + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		var resp Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.ID != tc.id || !strings.HasPrefix(resp.Error, tc.err) {
+			t.Errorf("%s: response = %+v, want ID %d and error %q", tc.request, resp, tc.id, tc.err)
+		}
+	}
+}
+
+func TestServerCloseFailsWaitingRequests(t *testing.T) {
+	server := testServer(t)
+
+	client, err := Dial(server.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Info()
+		errs <- err
+	}()
+	<-server.Calls()
+	server.Close()
+	if err := <-errs; err == nil || err.Error() != ErrShutdown.Error() {
+		t.Errorf("request during shutdown: err = %v, want %v", err, ErrShutdown)
+	}
+	if _, err := os.Stat(server.Path()); !os.IsNotExist(err) {
+		t.Errorf("the socket was left behind: %v", err)
+	}
+}
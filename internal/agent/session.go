@@ -0,0 +1,203 @@
+package agent
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// Session is the unlocked wallet behind a running agent. It answers calls,
+// queues the ones the user has to approve and wipes the key once the user
+// has been away for crypto.AutoLockAfter. It is not safe for concurrent
+// use; the agent's screen drives it.
+type Session struct {
+	WalletPath   string
+	Metadata     crypto.WalletMetadata
+	AccountIndex uint32
+	// AccountID is empty while the account has not been found on the
+	// network. Sign requests are then never auto-approved.
+	AccountID string
+	KeyType   crypto.KeyType
+	PublicKey sdk.PublicKey
+
+	Signed int
+	Denied int
+
+	secret       *crypto.WalletSecret
+	pending      []*Call
+	lastActivity time.Time
+}
+
+// NewSession takes ownership of secret, which is wiped when the session
+// locks or closes.
+func NewSession(walletPath string, secret *crypto.WalletSecret, metadata crypto.WalletMetadata, index uint32, accountID string) (*Session, error) {
+	key, err := secret.PrivateKeyAt(index)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		WalletPath:   walletPath,
+		Metadata:     metadata,
+		AccountIndex: index,
+		AccountID:    accountID,
+		KeyType:      secret.KeyType,
+		PublicKey:    key.PublicKey(),
+		secret:       secret,
+		lastActivity: time.Now(),
+	}, nil
+}
+
+// Touch records that the user pressed a key. Requests on the socket do not
+// count: a client polling the agent must not keep the key unlocked while
+// nobody is at the terminal to approve anything.
+func (s *Session) Touch(now time.Time) {
+	s.lastActivity = now
+}
+
+// LockIfIdle locks the session once the user has been inactive for
+// crypto.AutoLockAfter, and reports whether it did so now.
+func (s *Session) LockIfIdle(now time.Time) bool {
+	if s.Locked() || now.Sub(s.lastActivity) <= crypto.AutoLockAfter {
+		return false
+	}
+	s.wipe(ErrLocked)
+	return true
+}
+
+// Locked reports whether the key has been wiped. A locked session refuses
+// every request until the agent is restarted.
+func (s *Session) Locked() bool {
+	return s.secret == nil
+}
+
+// Close refuses the waiting requests and wipes the key.
+func (s *Session) Close() {
+	s.wipe(ErrShutdown)
+}
+
+func (s *Session) wipe(reason error) {
+	for _, call := range s.pending {
+		call.Fail(reason)
+	}
+	s.pending = nil
+	s.secret.Wipe()
+	s.secret = nil
+}
+
+// Pending returns the sign requests waiting for the user, oldest first.
+func (s *Session) Pending() []*Call {
+	return s.pending
+}
+
+// Handle answers call, or queues it when the user has to approve it. It
+// returns a line for the agent's log, or "" when there is nothing to note.
+func (s *Session) Handle(call *Call) string {
+	if s.Locked() {
+		call.Fail(ErrLocked)
+		return fmt.Sprintf("refused %s request: locked", call.Request.Method)
+	}
+
+	switch call.Request.Method {
+	case MethodInfo:
+		call.Reply(Response{Info: s.info()})
+		return ""
+	case MethodSign:
+		maxFee := s.Metadata.MaxFee()
+		fee, err := sdk.TransactionGetMaxTransactionFee(call.Transaction)
+		if err == nil && fee.AsTinybar() > maxFee.AsTinybar() {
+			call.Fail(fmt.Errorf("%w: max fee %s is more than %s", hedera.ErrFeeTooHigh, fee, maxFee))
+			return fmt.Sprintf("refused %s: max fee %s", call.Summary.TransactionID, fee)
+		}
+		if call.Summary.AutoApproved(s.Metadata.AgentAutoApprove, s.AccountID) {
+			return s.sign(call, "auto-approved")
+		}
+		s.pending = append(s.pending, call)
+		return ""
+	}
+
+	call.Fail(fmt.Errorf("unsupported method %q", call.Request.Method))
+	return ""
+}
+
+func (s *Session) info() *Info {
+	network := s.Metadata.NetworkConfig()
+	return &Info{
+		AccountID:      s.AccountID,
+		PublicKey:      s.PublicKey.StringRaw(),
+		KeyType:        s.KeyType,
+		Network:        network.Name,
+		NodeAddress:    network.NodeAddress,
+		NodeAccountID:  network.NodeAccountID,
+		MirrorURL:      network.MirrorURL,
+		MaxFeeTinybars: s.Metadata.MaxFee().AsTinybar(),
+	}
+}
+
+// Approve signs the oldest waiting request unless it waited longer than
+// ApprovalTimeout, in which case its client has already given up.
+func (s *Session) Approve(now time.Time) string {
+	if len(s.pending) == 0 {
+		return ""
+	}
+	call := s.pending[0]
+	s.pending = s.pending[1:]
+	if now.Sub(call.Received) > ApprovalTimeout {
+		return fmt.Sprintf("request for %s expired", call.Summary.TransactionID)
+	}
+	return s.sign(call, "approved")
+}
+
+// Deny refuses the oldest waiting request.
+func (s *Session) Deny() string {
+	if len(s.pending) == 0 {
+		return ""
+	}
+	call := s.pending[0]
+	s.pending = s.pending[1:]
+	call.Fail(ErrDenied)
+	s.Denied++
+	return fmt.Sprintf("denied %s", call.Summary.TransactionID)
+}
+
+func (s *Session) sign(call *Call, how string) string {
+	key, err := s.secret.PrivateKeyAt(s.AccountIndex)
+	if err != nil {
+		call.Fail(err)
+		return fmt.Sprintf("failed to sign %s: %v", call.Summary.TransactionID, err)
+	}
+
+	signed, err := sdk.TransactionSign(call.Transaction, key)
+	if err == nil {
+		var data []byte
+		data, err = sdk.TransactionToBytes(signed)
+		if err == nil {
+			call.Reply(Response{Transaction: base64.StdEncoding.EncodeToString(data)})
+		}
+	}
+	if err != nil {
+		call.Fail(err)
+		return fmt.Sprintf("failed to sign %s: %v", call.Summary.TransactionID, err)
+	}
+
+	s.Signed++
+	return fmt.Sprintf("signed %s (%s)", call.Summary.TransactionID, how)
+}
+
+// AdoptRules stores rules as the auto-approve rules in the authenticated
+// wallet metadata, which is the only place the agent reads them from.
+func (s *Session) AdoptRules(rules []crypto.AgentRule) error {
+	if s.Locked() {
+		return ErrLocked
+	}
+	metadata := s.Metadata
+	metadata.AgentAutoApprove = rules
+	if err := crypto.SaveWalletMetadata(s.WalletPath, metadata, s.secret); err != nil {
+		return err
+	}
+	s.Metadata = metadata
+	return nil
+}
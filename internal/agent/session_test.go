@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// testSignCall is a sign request for a transfer of tinybars from payer to
+// each recipient, decoded the way the server decodes one from a client.
+func testSignCall(t *testing.T, payer string, tinybars int64, recipients ...string) *Call {
+	t.Helper()
+	from, err := sdk.AccountIDFromString(payer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := sdk.NewTransferTransaction().
+		SetTransactionID(sdk.TransactionIDGenerate(from)).
+		SetNodeAccountIDs([]sdk.AccountID{{Account: 3}}).
+		SetMaxTransactionFee(sdk.HbarFromTinybar(100_000_000))
+	for _, recipient := range recipients {
+		to, err := sdk.AccountIDFromString(recipient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.AddHbarTransfer(from, sdk.HbarFromTinybar(-tinybars)).AddHbarTransfer(to, sdk.HbarFromTinybar(tinybars))
+	}
+	frozen, err := tx.Freeze()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := frozen.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := sdk.TransactionFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Call{
+		Request:     Request{ID: 1, Method: MethodSign},
+		Transaction: decoded,
+		Summary:     Summarize(decoded),
+		Received:    time.Now(),
+		reply:       make(chan Response, 1),
+	}
+}
+
+func testSession(t *testing.T, rules []crypto.AgentRule) (*Session, *crypto.WalletSecret) {
+	t.Helper()
+	key, err := sdk.PrivateKeyGenerateEd25519()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := crypto.NewPrivateKeySecret(key, crypto.KeyTypeED25519)
+	session, err := NewSession("", secret, crypto.WalletMetadata{AgentAutoApprove: rules}, 0, "0.0.1001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session, secret
+}
+
+func reply(t *testing.T, call *Call) Response {
+	t.Helper()
+	select {
+	case resp := <-call.reply:
+		return resp
+	default:
+		t.Fatal("the call was not answered")
+		return Response{}
+	}
+}
+
+func TestSessionHandle(t *testing.T) {
+	session, _ := testSession(t, []crypto.AgentRule{{MaxTinybars: 1_000, Recipients: []string{"0.0.2002"}}})
+	defer session.Close()
+
+	info := &Call{Request: Request{ID: 1, Method: MethodInfo}, reply: make(chan Response, 1)}
+	session.Handle(info)
+	if resp := reply(t, info); resp.Info == nil || resp.Info.AccountID != "0.0.1001" || resp.Info.PublicKey != session.PublicKey.StringRaw() {
+		t.Errorf("info = %+v", resp.Info)
+	}
+
+	auto := testSignCall(t, "0.0.1001", 1_000, "0.0.2002")
+	session.Handle(auto)
+	if resp := reply(t, auto); resp.Error != "" || resp.Transaction == "" {
+		t.Errorf("auto-approved call = %+v", resp)
+	}
+
+	asks := testSignCall(t, "0.0.1001", 1_001, "0.0.2002")
+	session.Handle(asks)
+	if len(session.Pending()) != 1 {
+		t.Fatalf("%d calls pending, want 1", len(session.Pending()))
+	}
+	session.Deny()
+	if resp := reply(t, asks); resp.Error != ErrDenied.Error() {
+		t.Errorf("denied call error = %q", resp.Error)
+	}
+	if session.Signed != 1 || session.Denied != 1 {
+		t.Errorf("signed %d, denied %d; want 1 and 1", session.Signed, session.Denied)
+	}
+}
+
+func TestSessionRefusesFeeAboveMax(t *testing.T) {
+	session, _ := testSession(t, []crypto.AgentRule{{MaxTinybars: 1_000}})
+	defer session.Close()
+	session.Metadata.MaxFeeTinybars = 99_999_999
+
+	call := testSignCall(t, "0.0.1001", 1, "0.0.2002")
+	session.Handle(call)
+	if resp := reply(t, call); resp.Error == "" {
+		t.Error("signed a transaction whose max fee is above the wallet's")
+	}
+}
+
+// Requests on the socket must not keep the key unlocked; only the user at
+// the terminal does.
+func TestSessionLocksWithoutKeyPresses(t *testing.T) {
+	session, secret := testSession(t, nil)
+	start := time.Now()
+	session.Touch(start)
+
+	waiting := testSignCall(t, "0.0.1001", 1, "0.0.2002")
+	for now := start; now.Before(start.Add(crypto.AutoLockAfter)); now = now.Add(time.Minute) {
+		info := &Call{Request: Request{ID: 1, Method: MethodInfo}, reply: make(chan Response, 1)}
+		session.Handle(info)
+		reply(t, info)
+		if session.LockIfIdle(now) {
+			t.Fatalf("locked after %s", now.Sub(start))
+		}
+	}
+	session.Handle(waiting)
+
+	if !session.LockIfIdle(start.Add(crypto.AutoLockAfter + time.Second)) {
+		t.Fatal("still unlocked after crypto.AutoLockAfter without a key press")
+	}
+	if !session.Locked() || secret.Data != nil {
+		t.Error("the secret was not wiped")
+	}
+	if resp := reply(t, waiting); resp.Error != ErrLocked.Error() {
+		t.Errorf("waiting call error = %q, want %q", resp.Error, ErrLocked)
+	}
+
+	call := testSignCall(t, "0.0.1001", 1, "0.0.2002")
+	session.Handle(call)
+	if resp := reply(t, call); resp.Error != ErrLocked.Error() {
+		t.Errorf("call after locking: error = %q", resp.Error)
+	}
+	if err := session.AdoptRules(nil); !errors.Is(err, ErrLocked) {
+		t.Errorf("AdoptRules after locking: err = %v", err)
+	}
+}
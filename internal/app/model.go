@@ -1,12 +1,15 @@
 package app
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/divin3circle/shred/internal/config"
 	"github.com/divin3circle/shred/internal/crypto"
 	hedera_client "github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
 )
 
 type SessionState int
@@ -15,19 +18,43 @@ const (
 	StateWelcome SessionState = iota
 	StateWalletList
 	StateWalletUnlock
+	StateMetadataTampered
 	StateCreate
+	StateImport
+	StateImportKey
 	StateVerify
+	StateShareSetup
+	StateShareDisplay
+	StateShareVerify
+	StateShareRecover
+	StateKeyTypeSelect
+	StateMnemonicPassphrase
 	StatePassword
+	StateWalletExists
+	StateNetworkSelect
+	StateNetworkCustom
 	StateDashboard
 	StateReceive
 	StateTokenMenu
+	StateTokenAssociate
+	StateTokenConfirm
+	StateTokenSigning
+	StateNFTs
+	StateNFTDetail
 	StateLocked
 	StateSendSelectToken
 	StateSendRecipient
 	StateSendAmount
+	StateSendMemo
 	StateSendConfirm
 	StateSendSigning
 	StateHistory
+	StateHistoryDetail
+	StateHistoryFilter
+	StateHistoryExport
+	StateChangePassphrase
+	StateAccounts
+	StateMaxFee
 )
 
 type Model struct {
@@ -37,47 +64,124 @@ type Model struct {
 
 	Mnemonic []byte
 	Wallet   *crypto.Wallet
+	Secret   *crypto.WalletSecret
+	KeyType  crypto.KeyType
+	Metadata crypto.WalletMetadata
 
-	Input textinput.Model
+	ResetMetadata bool
+
+	Input  textinput.Model
+	Config config.Config
 
 	MnemonicWords      []string
 	VerifyIndices      []int
 	CurrentVerifyIndex int
+	VerifyFailures     int
+
+	ImportWords   []string
+	ImportKeyStep int
+	KeyTypeCursor int
+
+	MnemonicPass     []byte
+	MnemonicPassStep int
+
+	Shares           []string
+	ShareThreshold   int
+	ShareSetupStep   int
+	ShareIndex       int
+	ShareVerifyIndex []int
+	ShareVerifyPos   int
+	RecoveredShares  []crypto.Share
 
 	LastActivity time.Time
 
 	HederaClient *hedera_client.Client
+	Network      hedera_client.NetworkConfig
 	Balance      string
 	AccountID    string
 	EVMAddress   string
+	PublicKey    sdk.PublicKey
+	AccountIndex uint32
 
 	TokenBalances []hedera_client.TokenBalance
 	TokenAliases  map[string]string
 
+	// Prices are the fiat prices of the account's assets in Config.Currency,
+	// keyed by token ID with "" for HBAR. Assets without a price are missing.
+	PriceProvider   hedera_client.PriceProvider
+	Prices          map[string]*big.Rat
+	BalanceTinybars int64
+
 	SelectedTokenIndex int
 	TokenListCursor    int
 
+	TokenRelationships hedera_client.TokenRelationships
+	TokenMenuLoading   bool
+	EditingTokenAlias  bool
+	TokenAction        string // "associate" or "dissociate"
+	TokenActionID      string
+
+	NFTs        []hedera_client.NFT
+	NFTCursor   int
+	NFTsLoading bool
+
+	// The fee of the transaction on a confirm screen. Signing waits until
+	// the estimate has arrived; when it failed, FeeError is set and signing
+	// needs an explicit "sign anyway".
+	FeeEstimate  hedera_client.FeeEstimate
+	FeeEstimated bool
+	FeeError     string
+
 	SendSelectedToken hedera_client.TokenBalance
+	SendNFT           *hedera_client.NFT // set when the send moves an NFT
+	SendTokenLoading  bool               // the chosen token's decimals are being looked up
 	SendRecipient     string
-	SendAmount        string
+	SendAmount        int64 // in the asset's smallest unit
 	SendMemo          string
+	SendMemoWarned    bool // the missing memo warning has been shown once
 	SendError         string
 	SendSuccess       string
 
 	HistoryTransactions []hedera_client.MirrorTransaction
+	HistoryQuery        hedera_client.HistoryQuery
+	HistoryPageURL      string // "" on the first page
 	HistoryNextURL      string
-	HistoryPrevURLs     []string
+	HistoryPrevURLs     []string // pages before the current one, oldest first
+	HistoryCursor       int
 	HistoryIsLoading    bool
 	HistoryError        string
 
+	// The history filter screen. The range is kept as typed, so it can be
+	// edited again, and parsed into HistoryQuery when the filters apply.
+	HistoryFilterCursor  int
+	HistoryFilterEditing bool
+	HistorySince         string
+	HistoryUntil         string
+
+	HistoryExportFormat string
+	HistoryExporting    bool
+
 	IsRefreshing bool
 	RefreshError string
 
+	NetworkCursor     int
+	CustomNetworkStep int
+
 	AvailableWallets    []crypto.WalletInfo
 	SelectedWalletIndex int
 	SelectedWalletPath  string
 
-	ErrorMessage string
+	AccountCursor       int
+	EditingAccountLabel bool
+
+	ChangePassStep   int
+	ChangePassPath   string
+	ChangePassOld    []byte
+	ChangePassNew    string
+	ChangePassReturn SessionState
+
+	ErrorMessage  string
+	StatusMessage string
 }
 
 func NewModel() Model {
@@ -85,9 +189,14 @@ func NewModel() Model {
 	ti.Placeholder = "Type here..."
 	ti.Focus()
 
+	// A broken config file falls back to the defaults rather than keeping
+	// the wallet from starting.
+	cfg, _ := config.Load()
+
 	return Model{
 		State:        StateWelcome,
 		Input:        ti,
+		Config:       cfg,
 		LastActivity: time.Now(),
 	}
 }
@@ -105,14 +214,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.LastActivity = time.Now()
 	}
 
-	if time.Since(m.LastActivity) > 10*time.Minute {
+	if time.Since(m.LastActivity) > crypto.AutoLockAfter {
 		m.State = StateLocked
 		if m.Wallet != nil {
 			m.Wallet.Wipe()
 			m.Wallet = nil
 		}
+		m.Secret.Wipe()
+		m.Secret = nil
+		wipeBytes(m.ChangePassOld)
+		m.ChangePassOld = nil
+		m.ChangePassNew = ""
 		m.Mnemonic = nil
 		m.MnemonicWords = nil
+		m.Shares = nil
+		m.RecoveredShares = nil
+		wipeBytes(m.MnemonicPass)
+		m.MnemonicPass = nil
 	}
 
 	switch msg := msg.(type) {
@@ -130,6 +248,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tickMsg:
 		// Time to update the clock - return next tick command
 		return m, tickCmd()
+	case pricesMsg:
+		m.Prices = msg.Prices
+		return m, nil
+	case refreshAccountMsg:
+		return m.accountRefreshed(msg)
+	case accountsResolvedMsg:
+		return m.accountsResolved(msg), nil
 	case walletsFoundMsg:
 		if msg.Error != nil {
 			m.State = StateWelcome
@@ -152,30 +277,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateWalletList(msg)
 	case StateWalletUnlock:
 		return m.updateWalletUnlock(msg)
+	case StateMetadataTampered:
+		return m.updateMetadataTampered(msg)
+	case StateWalletExists:
+		return m.updateWalletExists(msg)
 	case StateCreate:
 		return m.updateCreate(msg)
+	case StateImport:
+		return m.updateImport(msg)
+	case StateImportKey:
+		return m.updateImportKey(msg)
 	case StateVerify:
 		return m.updateVerify(msg)
+	case StateShareSetup:
+		return m.updateShareSetup(msg)
+	case StateShareDisplay:
+		return m.updateShareDisplay(msg)
+	case StateShareVerify:
+		return m.updateShareVerify(msg)
+	case StateShareRecover:
+		return m.updateShareRecover(msg)
+	case StateKeyTypeSelect:
+		return m.updateKeyTypeSelect(msg)
+	case StateMnemonicPassphrase:
+		return m.updateMnemonicPassphrase(msg)
 	case StatePassword:
 		return m.updatePassword(msg)
+	case StateNetworkSelect:
+		return m.updateNetworkSelect(msg)
+	case StateNetworkCustom:
+		return m.updateNetworkCustom(msg)
 	case StateDashboard:
 		return m.updateDashboard(msg)
 	case StateReceive:
 		return m.updateReceive(msg)
 	case StateTokenMenu:
 		return m.updateTokenMenu(msg)
+	case StateTokenAssociate:
+		return m.updateTokenAssociate(msg)
+	case StateTokenConfirm:
+		return m.updateTokenConfirm(msg)
+	case StateTokenSigning:
+		return m.updateTokenSigning(msg)
+	case StateNFTs:
+		return m.updateNFTs(msg)
+	case StateNFTDetail:
+		return m.updateNFTDetail(msg)
 	case StateSendSelectToken:
 		return m.updateSendSelectToken(msg)
 	case StateSendRecipient:
 		return m.updateSendRecipient(msg)
 	case StateSendAmount:
 		return m.updateSendAmount(msg)
+	case StateSendMemo:
+		return m.updateSendMemo(msg)
 	case StateSendConfirm:
 		return m.updateSendConfirm(msg)
 	case StateSendSigning:
 		return m.updateSendSigning(msg)
 	case StateHistory:
 		return m.updateHistory(msg)
+	case StateHistoryDetail:
+		return m.updateHistoryDetail(msg)
+	case StateHistoryFilter:
+		return m.updateHistoryFilter(msg)
+	case StateHistoryExport:
+		return m.updateHistoryExport(msg)
+	case StateChangePassphrase:
+		return m.updateChangePassphrase(msg)
+	case StateAccounts:
+		return m.updateAccounts(msg)
+	case StateMaxFee:
+		return m.updateMaxFee(msg)
 	}
 
 	return m, nil
@@ -189,30 +362,78 @@ func (m Model) View() string {
 		return m.viewWalletList()
 	case StateWalletUnlock:
 		return m.viewWalletUnlock()
+	case StateMetadataTampered:
+		return m.viewMetadataTampered()
+	case StateWalletExists:
+		return m.viewWalletExists()
 	case StateCreate:
 		return m.viewCreate()
+	case StateImport:
+		return m.viewImport()
+	case StateImportKey:
+		return m.viewImportKey()
 	case StateVerify:
 		return m.viewVerify()
+	case StateShareSetup:
+		return m.viewShareSetup()
+	case StateShareDisplay:
+		return m.viewShareDisplay()
+	case StateShareVerify:
+		return m.viewShareVerify()
+	case StateShareRecover:
+		return m.viewShareRecover()
+	case StateKeyTypeSelect:
+		return m.viewKeyTypeSelect()
+	case StateMnemonicPassphrase:
+		return m.viewMnemonicPassphrase()
 	case StatePassword:
 		return m.viewPassword()
+	case StateNetworkSelect:
+		return m.viewNetworkSelect()
+	case StateNetworkCustom:
+		return m.viewNetworkCustom()
 	case StateDashboard:
 		return m.viewDashboard()
 	case StateReceive:
 		return m.viewReceive()
 	case StateTokenMenu:
 		return m.viewTokenMenu()
+	case StateTokenAssociate:
+		return m.viewTokenAssociate()
+	case StateTokenConfirm:
+		return m.viewTokenConfirm()
+	case StateTokenSigning:
+		return m.viewTokenSigning()
+	case StateNFTs:
+		return m.viewNFTs()
+	case StateNFTDetail:
+		return m.viewNFTDetail()
 	case StateSendSelectToken:
 		return m.viewSendSelectToken()
 	case StateSendRecipient:
 		return m.viewSendRecipient()
 	case StateSendAmount:
 		return m.viewSendAmount()
+	case StateSendMemo:
+		return m.viewSendMemo()
 	case StateSendConfirm:
 		return m.viewSendConfirm()
 	case StateSendSigning:
 		return m.viewSendSigning()
 	case StateHistory:
 		return m.viewHistory()
+	case StateHistoryDetail:
+		return m.viewHistoryDetail()
+	case StateHistoryFilter:
+		return m.viewHistoryFilter()
+	case StateHistoryExport:
+		return m.viewHistoryExport()
+	case StateChangePassphrase:
+		return m.viewChangePassphrase()
+	case StateAccounts:
+		return m.viewAccounts()
+	case StateMaxFee:
+		return m.viewMaxFee()
 	}
 	return "You have been logged out. Press ctrl+c to quit."
 }
@@ -1,8 +1,14 @@
 package app
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,8 +23,11 @@ import (
 type tickMsg time.Time
 
 type refreshAccountMsg struct {
+	PublicKey    sdk.PublicKey
 	AccountID    string
+	EVMAddress   string
 	Balance      string
+	Tinybars     int64
 	Tokens       []hedera_client.TokenBalance
 	Error        error
 }
@@ -74,6 +83,17 @@ func (m Model) updateWalletList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.MnemonicWords = strings.Split(string(mnemonic), " ")
 			m.State = StateCreate
 			return m, nil
+		case "i":
+			return m.enterImport(), nil
+		case "p":
+			return m.enterImportKey(), nil
+		case "r":
+			return m.enterShareRecover(), nil
+		case "c":
+			if len(m.AvailableWallets) > 0 && m.SelectedWalletIndex < len(m.AvailableWallets) {
+				return m.enterChangePassphrase(m.AvailableWallets[m.SelectedWalletIndex].FilePath), nil
+			}
+			return m, nil
 		case "q":
 			return m, tea.Quit
 		}
@@ -81,6 +101,93 @@ func (m Model) updateWalletList(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) enterChangePassphrase(walletPath string) Model {
+	m.ChangePassReturn = m.State
+	m.ChangePassPath = walletPath
+	m.ChangePassStep = 0
+	m.ChangePassNew = ""
+	m.ErrorMessage = ""
+	m.StatusMessage = ""
+	m.State = StateChangePassphrase
+	m.Input.Reset()
+	m.Input.EchoMode = textinput.EchoPassword
+	m.Input.Placeholder = "Current Passphrase"
+	return m
+}
+
+func (m Model) leaveChangePassphrase() Model {
+	wipeBytes(m.ChangePassOld)
+	m.ChangePassOld = nil
+	m.ChangePassNew = ""
+	m.ChangePassStep = 0
+	m.State = m.ChangePassReturn
+	m.Input.Reset()
+	m.Input.EchoMode = textinput.EchoNormal
+	return m
+}
+
+func (m Model) updateChangePassphrase(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.ErrorMessage = ""
+			return m.leaveChangePassphrase(), nil
+		case "enter":
+			value := m.Input.Value()
+			m.Input.Reset()
+			if value == "" {
+				return m, nil
+			}
+
+			switch m.ChangePassStep {
+			case 0:
+				// Check the passphrase now rather than after the new one
+				// has been typed twice.
+				pass := []byte(value)
+				secret, err := crypto.LoadWallet(pass, m.ChangePassPath)
+				if err != nil {
+					wipeBytes(pass)
+					m.ErrorMessage = "Invalid passphrase. Try again."
+					return m, nil
+				}
+				secret.Wipe()
+				m.ChangePassOld = pass
+				m.ChangePassStep = 1
+				m.Input.Placeholder = "New Passphrase"
+			case 1:
+				m.ChangePassNew = value
+				m.ChangePassStep = 2
+				m.Input.Placeholder = "Confirm New Passphrase"
+			case 2:
+				if value != m.ChangePassNew {
+					m.ErrorMessage = "Passphrases do not match. Enter the new passphrase again."
+					m.ChangePassNew = ""
+					m.ChangePassStep = 1
+					m.Input.Placeholder = "New Passphrase"
+					return m, nil
+				}
+				pass := []byte(m.ChangePassNew)
+				err := crypto.ChangePassphrase(m.ChangePassPath, m.ChangePassOld, pass)
+				wipeBytes(pass)
+				if err != nil {
+					m.ErrorMessage = fmt.Sprintf("Failed to re-encrypt wallet: %v", err)
+					return m.leaveChangePassphrase(), nil
+				}
+				m.ErrorMessage = ""
+				m.StatusMessage = "Passphrase changed."
+				return m.leaveChangePassphrase(), nil
+			}
+			m.ErrorMessage = ""
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
 func (m Model) updateWalletUnlock(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.Input, cmd = m.Input.Update(msg)
@@ -94,7 +201,9 @@ func (m Model) updateWalletUnlock(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 
-			mnemonic, err := crypto.LoadWallet(passphrase, m.SelectedWalletPath)
+			pass := []byte(passphrase)
+			defer wipeBytes(pass)
+			secret, err := crypto.LoadWallet(pass, m.SelectedWalletPath)
 			if err != nil {
 				m.Input.Reset()
 				m.Input.Placeholder = "Invalid passphrase. Try again:"
@@ -102,51 +211,32 @@ func (m Model) updateWalletUnlock(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 
-			m.Mnemonic = mnemonic
+			m.Secret = secret
+			m.Input.Reset()
 
-			client, err := hedera_client.NewClient()
-			if err == nil {
-				m.HederaClient = client
-
-				ecdsaKey, err := crypto.DeriveECDSAKey(m.Mnemonic)
-				if err == nil {
-					m.EVMAddress = crypto.CalculateEVMAddress(ecdsaKey)
-
-					accountID, err := client.GetAccountIDFromEVMAddress(m.EVMAddress)
-					if err == nil && accountID != "" {
-						m.AccountID = accountID
-
-						id, _ := sdk.AccountIDFromString(accountID)
-						info, err := client.GetAccountBalance(id)
-						if err == nil {
-							m.Balance = info.Balance.String()
-							m.TokenBalances = info.Tokens
-						}
-					} else {
-						m.AccountID = "Unverified"
-						m.Balance = "0.00 ℏ"
-					}
+			metadata, err := crypto.LoadVerifiedWalletMetadata(m.SelectedWalletPath, secret)
+			if errors.Is(err, crypto.ErrMetadataTampered) {
+				m.State = StateMetadataTampered
+				return m, nil
+			}
+			if err != nil {
+				metadata = crypto.WalletMetadata{
+					CreatedAt: time.Now(),
+					Network:   hedera_client.NetworkTestnet,
+				}
+			}
 
-					metadata, err := crypto.LoadWalletMetadata(m.SelectedWalletPath)
-					if err != nil {
-						metadata = crypto.WalletMetadata{
-							EVMAddress: m.EVMAddress,
-							CreatedAt:  time.Now(),
-							Network:    "testnet",
-						}
-					}
-					metadata.AccountID = m.AccountID
-					metadata.EVMAddress = m.EVMAddress
-					if metadata.TokenAliases != nil {
-						m.TokenAliases = metadata.TokenAliases
-					} else {
-						m.TokenAliases = make(map[string]string)
-					}
-					crypto.SaveWalletMetadata(m.SelectedWalletPath, metadata)
+			// Unlocking never rewrites the file by itself; the interactive
+			// unlock upgrades older files and says so.
+			if secret.NeedsUpgrade() {
+				if err := crypto.UpgradeWallet(secret, pass, m.SelectedWalletPath); err != nil {
+					m.ErrorMessage = fmt.Sprintf("The wallet file uses an older format and could not be upgraded: %v", err)
+				} else {
+					m.StatusMessage = "Upgraded the wallet file to the current format."
 				}
 			}
 
-			m.State = StateDashboard
+			return m.openWallet(m.SelectedWalletPath, metadata)
 		case "esc":
 			m.State = StateWalletList
 			m.Input.Reset()
@@ -170,6 +260,11 @@ func (m Model) updateWelcome(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.State = StateCreate
 			return m, nil
 		case "i":
+			return m.enterImport(), nil
+		case "p":
+			return m.enterImportKey(), nil
+		case "r":
+			return m.enterShareRecover(), nil
 		case "q":
 			return m, tea.Quit
 		}
@@ -177,332 +272,1831 @@ func (m Model) updateWelcome(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m Model) enterImport() Model {
+	m.State = StateImport
+	m.ImportWords = nil
+	m.ErrorMessage = ""
+	m.Input.Reset()
+	m.Input.EchoMode = textinput.EchoNormal
+	m.Input.Placeholder = "Word #1"
+	return m
+}
+
+func (m Model) updateImport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	previous := m.Input.Value()
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "esc":
+			m.ImportWords = nil
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, checkForWallets
+		case "tab":
+			prefix := strings.TrimSpace(strings.ToLower(previous))
+			suggestions := crypto.SuggestMnemonicWords(prefix, 1)
+			if len(suggestions) > 0 {
+				m.Input.SetValue(suggestions[0])
+				m.Input.CursorEnd()
+			}
+			return m, nil
+		case "backspace":
+			if previous == "" && len(m.ImportWords) > 0 {
+				m.ImportWords = m.ImportWords[:len(m.ImportWords)-1]
+				m.Input.Placeholder = fmt.Sprintf("Word #%d", len(m.ImportWords)+1)
+				return m, nil
+			}
 		case "enter":
-			m.VerifyIndices = []int{3, 10, 18}
-			m.CurrentVerifyIndex = 0
-			m.State = StateVerify
+			words := strings.Fields(strings.ToLower(previous))
+			if len(words) == 0 {
+				return m.finishImport(), nil
+			}
+
+			for _, word := range words {
+				if !crypto.IsMnemonicWord(word) {
+					suggestions := crypto.SuggestMnemonicWords(word, 2)
+					if len(suggestions) != 1 {
+						m.ErrorMessage = fmt.Sprintf("%q is not in the BIP-39 word list", word)
+						return m, nil
+					}
+					word = suggestions[0]
+				}
+				if len(m.ImportWords) >= 24 {
+					break
+				}
+				m.ImportWords = append(m.ImportWords, word)
+			}
+
+			m.ErrorMessage = ""
 			m.Input.Reset()
-			m.Input.Placeholder = fmt.Sprintf("Word #%d", m.VerifyIndices[0]+1)
+			m.Input.Placeholder = fmt.Sprintf("Word #%d", len(m.ImportWords)+1)
+			if len(m.ImportWords) == 24 {
+				return m.finishImport(), nil
+			}
 			return m, nil
 		}
 	}
-	return m, nil
-}
 
-func (m Model) updateVerify(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.Input, cmd = m.Input.Update(msg)
-	
+	return m, cmd
+}
+
+func (m Model) finishImport() Model {
+	switch len(m.ImportWords) {
+	case 12, 15, 18, 21, 24:
+	default:
+		m.ErrorMessage = fmt.Sprintf("A recovery phrase has 12, 15, 18, 21 or 24 words (got %d)", len(m.ImportWords))
+		return m
+	}
+
+	phrase := []byte(strings.Join(m.ImportWords, " "))
+	if !crypto.ValidateMnemonic(phrase) {
+		m.ErrorMessage = "Invalid recovery phrase: checksum does not match"
+		return m
+	}
+
+	m.Mnemonic = phrase
+	m.MnemonicWords = m.ImportWords
+	m.ImportWords = nil
+	m.ErrorMessage = ""
+	m.Input.Reset()
+	m.State = StateKeyTypeSelect
+	m.KeyTypeCursor = 0
+	return m
+}
+
+func (m Model) updateKeyTypeSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "enter":
-			inputWord := strings.TrimSpace(strings.ToLower(m.Input.Value()))
-			targetIndex := m.VerifyIndices[m.CurrentVerifyIndex]
-			targetWord := m.MnemonicWords[targetIndex]
-
-			if inputWord == targetWord {
-				m.CurrentVerifyIndex++
-				m.Input.Reset()
-				
-				if m.CurrentVerifyIndex >= len(m.VerifyIndices) {
-					m.State = StatePassword
-					m.Input.Placeholder = "Enter Passphrase"
-					m.Input.EchoMode = textinput.EchoPassword
-				} else {
-					m.Input.Placeholder = fmt.Sprintf("Word #%d", m.VerifyIndices[m.CurrentVerifyIndex]+1)
-				}
-			} else {
-				m.Input.Reset()
-				m.Input.Placeholder = fmt.Sprintf("Incorrect! Try Word #%d again", targetIndex+1)
+		case "up", "k":
+			if m.KeyTypeCursor > 0 {
+				m.KeyTypeCursor--
 			}
+		case "down", "j":
+			if m.KeyTypeCursor < len(crypto.KeyTypes)-1 {
+				m.KeyTypeCursor++
+			}
+		case "enter":
+			m.State = StateMnemonicPassphrase
+			m.MnemonicPassStep = 0
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoPassword
+			m.Input.Placeholder = "Optional BIP-39 passphrase"
 		}
 	}
-	return m, cmd
+	return m, nil
 }
 
-func (m Model) updatePassword(msg tea.Msg) (tea.Model, tea.Cmd) {
+// updateMnemonicPassphrase asks for the optional BIP-39 passphrase. An empty
+// value means none; anything else must be typed twice, since a typo silently
+// leads to a different, empty wallet.
+func (m Model) updateMnemonicPassphrase(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.Input, cmd = m.Input.Update(msg)
-	
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "esc":
+			wipeBytes(m.MnemonicPass)
+			m.MnemonicPass = nil
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoNormal
+			m.State = StateKeyTypeSelect
+			return m, nil
 		case "enter":
-			passphrase := m.Input.Value()
-			
-			ecdsaKey, err := crypto.DeriveECDSAKey(m.Mnemonic)
-			if err != nil {
-				m.Input.Reset()
-				m.Input.Placeholder = "Error deriving keys. Try again:"
-				return m, cmd
-			}
-			
-			evmAddress := crypto.CalculateEVMAddress(ecdsaKey)
-			m.EVMAddress = evmAddress
-			
-			identifier := evmAddress
-			if len(identifier) >= 2 && identifier[0:2] == "0x" {
-				identifier = identifier[2:]
-			}
-			
-			walletPath, err := crypto.GetWalletPath(identifier)
-			if err != nil {
-				m.ErrorMessage = fmt.Sprintf("Error getting wallet path: %v", err)
-				m.Input.Reset()
-				m.Input.Placeholder = "Error getting wallet path. Try again:"
-				return m, cmd
-			}
-			
-			err = crypto.SaveWallet(m.Mnemonic, passphrase, walletPath)
-			if err != nil {
-				m.ErrorMessage = fmt.Sprintf("Failed to save wallet to %s: %v", walletPath, err)
-				m.Input.Reset()
-				m.Input.Placeholder = fmt.Sprintf("Error: %v", err)
-				return m, cmd
+			value := []byte(m.Input.Value())
+			m.Input.Reset()
+
+			if m.MnemonicPassStep == 0 && len(value) > 0 {
+				m.MnemonicPass = value
+				m.MnemonicPassStep = 1
+				m.ErrorMessage = ""
+				m.Input.Placeholder = "Confirm BIP-39 passphrase"
+				return m, nil
 			}
-			
-			if _, err := os.Stat(walletPath); err != nil {
-				m.ErrorMessage = fmt.Sprintf("Wallet file not found after save: %s (error: %v)", walletPath, err)
-				m.Input.Reset()
-				m.Input.Placeholder = "File creation failed. Try again:"
-				return m, cmd
+
+			if m.MnemonicPassStep == 1 && string(value) != string(m.MnemonicPass) {
+				wipeBytes(value)
+				wipeBytes(m.MnemonicPass)
+				m.MnemonicPass = nil
+				m.MnemonicPassStep = 0
+				m.ErrorMessage = "Passphrases do not match"
+				m.Input.Placeholder = "Optional BIP-39 passphrase"
+				return m, nil
 			}
-			
+			wipeBytes(value)
+
+			m.Secret = crypto.NewMnemonicSecret(m.Mnemonic, m.MnemonicPass, crypto.KeyTypes[m.KeyTypeCursor])
+			wipeBytes(m.MnemonicPass)
+			m.MnemonicPass = nil
 			m.ErrorMessage = ""
-			
-			metadata := crypto.WalletMetadata{
-				EVMAddress: evmAddress,
-				CreatedAt:  time.Now(),
-				Network:    "testnet",
-			}
-			err = crypto.SaveWalletMetadata(walletPath, metadata)
-			if err != nil {
-			}
-			
-			client, err := hedera_client.NewClient()
-			if err == nil {
-				m.HederaClient = client
-				
-				accountID, err := client.GetAccountIDFromEVMAddress(evmAddress)
-				if err == nil && accountID != "" {
-					m.AccountID = accountID
-					
-					metadata.AccountID = accountID
-					crypto.SaveWalletMetadata(walletPath, metadata)
-					
-					id, _ := sdk.AccountIDFromString(accountID)
-					info, err := client.GetAccountBalance(id)
-					if err == nil {
-						m.Balance = info.Balance.String()
-						m.TokenBalances = info.Tokens
-					}
-				} else {
-					m.AccountID = "Unverified"
-					m.Balance = "0.00 ℏ"
-				}
-			}
-			
-			m.State = StateDashboard
+			m.Input.EchoMode = textinput.EchoNormal
+			m.State = StateNetworkSelect
+			m.NetworkCursor = 1
+			return m, nil
 		}
 	}
 	return m, cmd
 }
 
-func refreshAccountCmd(evmAddress string, client *hedera_client.Client) tea.Cmd {
-	return func() tea.Msg {
-		if client == nil || evmAddress == "" {
-			return refreshAccountMsg{Error: fmt.Errorf("client or EVM address not available")}
-		}
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
 
-		accountID, err := client.GetAccountIDFromEVMAddress(evmAddress)
-		if err != nil {
-			return refreshAccountMsg{Error: fmt.Errorf("failed to query account: %w", err)}
-		}
+func (m Model) enterImportKey() Model {
+	m.State = StateImportKey
+	m.ImportKeyStep = 0
+	m.KeyTypeCursor = 0
+	m.ErrorMessage = ""
+	m.Input.Reset()
+	return m
+}
 
-		if accountID == "" {
-			return refreshAccountMsg{
-				AccountID: "Unverified",
-				Balance:   "0.00 ℏ",
+func (m Model) updateImportKey(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.ImportKeyStep == 0 {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc":
+				return m, checkForWallets
+			case "up", "k":
+				if m.KeyTypeCursor > 0 {
+					m.KeyTypeCursor--
+				}
+			case "down", "j":
+				if m.KeyTypeCursor < len(crypto.KeyTypes)-1 {
+					m.KeyTypeCursor++
+				}
+			case "enter":
+				m.ImportKeyStep = 1
+				m.Input.Reset()
+				m.Input.EchoMode = textinput.EchoPassword
+				m.Input.Placeholder = "Private key (hex or DER)"
 			}
 		}
+		return m, nil
+	}
 
-		id, err := sdk.AccountIDFromString(accountID)
-		if err != nil {
-			return refreshAccountMsg{Error: fmt.Errorf("invalid account ID: %w", err)}
-		}
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
 
-		info, err := client.GetAccountBalance(id)
-		if err != nil {
-			return refreshAccountMsg{
-				AccountID: accountID,
-				Balance:   "Error",
-				Error:     fmt.Errorf("failed to fetch balance: %w", err),
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.ImportKeyStep = 0
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoNormal
+			return m, nil
+		case "enter":
+			keyType := crypto.KeyTypes[m.KeyTypeCursor]
+			key, err := crypto.ParsePrivateKey(m.Input.Value(), keyType)
+			m.Input.Reset()
+			if err != nil {
+				m.ErrorMessage = fmt.Sprintf("Invalid private key: %v", err)
+				return m, nil
 			}
-		}
 
-		return refreshAccountMsg{
-			AccountID: accountID,
-			Balance:   info.Balance.String(),
-			Tokens:    info.Tokens,
+			m.Secret = crypto.NewPrivateKeySecret(key, keyType)
+			m.ErrorMessage = ""
+			m.State = StateNetworkSelect
+			m.NetworkCursor = 1
+			return m, nil
 		}
 	}
+	return m, cmd
 }
 
-func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m Model) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch strings.ToLower(msg.String()) {
-		case "f":
-			if m.HederaClient != nil && m.EVMAddress != "" && !m.IsRefreshing {
-				m.IsRefreshing = true
-				m.RefreshError = ""
-				return m, refreshAccountCmd(m.EVMAddress, m.HederaClient)
-			}
+		switch msg.String() {
 		case "s":
-			m.State = StateSendSelectToken
-			m.SelectedTokenIndex = 0
-			return m, nil
-		case "h":
-			m.State = StateHistory
-			m.HistoryIsLoading = true
-			m.HistoryError = ""
-			m.HistoryPrevURLs = []string{}
-			return m, fetchHistoryCmd(m.EVMAddress, "", m.HederaClient)
-		case "r":
-			m.State = StateReceive
+			m.State = StateShareSetup
+			m.ShareSetupStep = 0
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoNormal
+			m.Input.Placeholder = "Shares needed to recover (e.g. 3)"
 			return m, nil
-		case "t":
-			if len(m.TokenBalances) > 0 {
-				m.State = StateTokenMenu
-				m.SelectedTokenIndex = 0
-				m.Input.Reset()
-				m.Input.Placeholder = "Enter alias for selected token..."
-			}
+		case "enter":
+			m.VerifyIndices = m.verifyIndices(len(m.MnemonicWords))
+			m.CurrentVerifyIndex = 0
+			m.VerifyFailures = 0
+			m.ErrorMessage = ""
+			m.State = StateVerify
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoNormal
+			m.Input.Placeholder = fmt.Sprintf("Word #%d", m.VerifyIndices[0]+1)
 			return m, nil
-		case "q":
-			return m, tea.Quit
-		}
-	case refreshAccountMsg:
-		m.IsRefreshing = false
-		if msg.Error != nil {
-			m.RefreshError = msg.Error.Error()
-		} else {
-			m.AccountID = msg.AccountID
-			m.Balance = msg.Balance
-			m.TokenBalances = msg.Tokens
-			m.RefreshError = ""
 		}
-		return m, nil
 	}
 	return m, nil
 }
 
-func (m Model) updateReceive(msg tea.Msg) (tea.Model, tea.Cmd) {
+// verifyIndices chooses which of n words have to be re-entered: all of them
+// in full re-entry mode, otherwise a random sample of the configured size.
+func (m Model) verifyIndices(n int) []int {
+	if m.Config.VerifyFullPhrase {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+	return randomIndices(n, m.Config.VerifyWords)
+}
+
+func (m Model) updateShareSetup(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch strings.ToLower(msg.String()) {
+		switch msg.String() {
 		case "esc":
-			m.State = StateDashboard
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			m.State = StateCreate
+			return m, nil
+		case "enter":
+			value, err := strconv.Atoi(strings.TrimSpace(m.Input.Value()))
+			m.Input.Reset()
+			if err != nil {
+				m.ErrorMessage = "Enter a number"
+				return m, nil
+			}
+
+			if m.ShareSetupStep == 0 {
+				if value < 2 || value > crypto.MaxShares {
+					m.ErrorMessage = fmt.Sprintf("The threshold must be between 2 and %d", crypto.MaxShares)
+					return m, nil
+				}
+				m.ShareThreshold = value
+				m.ShareSetupStep = 1
+				m.ErrorMessage = ""
+				m.Input.Placeholder = fmt.Sprintf("Total shares (%d-%d)", value, crypto.MaxShares)
+				return m, nil
+			}
+
+			shares, err := crypto.SplitMnemonic(m.Mnemonic, m.ShareThreshold, value)
+			if err != nil {
+				m.ErrorMessage = err.Error()
+				return m, nil
+			}
+			m.Shares = shares
+			m.ShareIndex = 0
+			m.ErrorMessage = ""
+			m.State = StateShareDisplay
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+func (m Model) updateShareDisplay(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			words := strings.Fields(m.Shares[m.ShareIndex])
+			m.ShareVerifyIndex = m.verifyIndices(len(words))
+			m.ShareVerifyPos = 0
+			m.VerifyFailures = 0
+			m.ErrorMessage = ""
+			m.State = StateShareVerify
+			m.Input.Reset()
+			m.Input.Placeholder = fmt.Sprintf("Word #%d", m.ShareVerifyIndex[0]+1)
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// updateShareVerify checks a few words of the displayed share, the same way
+// updateVerify checks the phrase, before moving on to the next share.
+func (m Model) updateShareVerify(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.Input.Reset()
+			m.State = StateShareDisplay
+			return m, nil
+		case "enter":
+			words := strings.Fields(m.Shares[m.ShareIndex])
+			inputWord := strings.TrimSpace(strings.ToLower(m.Input.Value()))
+			targetIndex := m.ShareVerifyIndex[m.ShareVerifyPos]
+			m.Input.Reset()
+
+			if inputWord != words[targetIndex] {
+				m.VerifyFailures++
+				if m.VerifyFailures >= m.Config.VerifyMaxAttempts {
+					m.ErrorMessage = "Too many incorrect words. Check this share again."
+					m.State = StateShareDisplay
+					return m, nil
+				}
+				m.Input.Placeholder = fmt.Sprintf("Incorrect! Try Word #%d again", targetIndex+1)
+				return m, nil
+			}
+
+			m.ShareVerifyPos++
+			if m.ShareVerifyPos < len(m.ShareVerifyIndex) {
+				m.Input.Placeholder = fmt.Sprintf("Word #%d", m.ShareVerifyIndex[m.ShareVerifyPos]+1)
+				return m, nil
+			}
+
+			m.ShareIndex++
+			if m.ShareIndex < len(m.Shares) {
+				m.State = StateShareDisplay
+				return m, nil
+			}
+
+			// Every share is written down, so the full phrase does not need
+			// verifying as well.
+			m.Shares = nil
+			m.State = StateKeyTypeSelect
+			m.KeyTypeCursor = 0
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+func (m Model) enterShareRecover() Model {
+	m.State = StateShareRecover
+	m.RecoveredShares = nil
+	m.ErrorMessage = ""
+	m.Input.Reset()
+	m.Input.EchoMode = textinput.EchoNormal
+	m.Input.Placeholder = "Share words"
+	return m
+}
+
+func (m Model) updateShareRecover(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.RecoveredShares = nil
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, checkForWallets
+		case "enter":
+			share, err := crypto.ParseShare(m.Input.Value())
+			if err != nil {
+				m.ErrorMessage = fmt.Sprintf("Invalid share: %v", err)
+				return m, nil
+			}
+			m.Input.Reset()
+
+			for _, existing := range m.RecoveredShares {
+				if existing.Index == share.Index {
+					m.ErrorMessage = fmt.Sprintf("Share #%d was already entered", share.Index)
+					return m, nil
+				}
+			}
+			m.RecoveredShares = append(m.RecoveredShares, share)
+			m.ErrorMessage = ""
+			if len(m.RecoveredShares) < m.RecoveredShares[0].Threshold {
+				return m, nil
+			}
+
+			mnemonic, err := crypto.CombineShares(m.RecoveredShares)
+			m.RecoveredShares = nil
+			if err != nil {
+				m.ErrorMessage = fmt.Sprintf("Could not rebuild the recovery phrase: %v", err)
+				return m, nil
+			}
+
+			m.Mnemonic = mnemonic
+			m.MnemonicWords = strings.Fields(string(mnemonic))
+			m.State = StateKeyTypeSelect
+			m.KeyTypeCursor = 0
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+// randomIndices picks count distinct word positions below n, in ascending
+// order.
+func randomIndices(n, count int) []int {
+	if count > n {
+		count = n
+	}
+	picked := make(map[int]bool)
+	for len(picked) < count {
+		value, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+		if err != nil {
+			continue
+		}
+		picked[int(value.Int64())] = true
+	}
+
+	indices := make([]int, 0, count)
+	for i := 0; i < n; i++ {
+		if picked[i] {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func (m Model) updateVerify(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.Input.Reset()
+			m.ErrorMessage = ""
+			m.State = StateCreate
+			return m, nil
+		case "enter":
+			inputWord := strings.TrimSpace(strings.ToLower(m.Input.Value()))
+			targetIndex := m.VerifyIndices[m.CurrentVerifyIndex]
+			targetWord := m.MnemonicWords[targetIndex]
+
+			if inputWord == targetWord {
+				m.CurrentVerifyIndex++
+				m.Input.Reset()
+				
+				if m.CurrentVerifyIndex >= len(m.VerifyIndices) {
+					m.State = StateKeyTypeSelect
+					m.KeyTypeCursor = 0
+				} else {
+					m.Input.Placeholder = fmt.Sprintf("Word #%d", m.VerifyIndices[m.CurrentVerifyIndex]+1)
+				}
+			} else {
+				m.Input.Reset()
+				m.VerifyFailures++
+				if m.VerifyFailures >= m.Config.VerifyMaxAttempts {
+					m.ErrorMessage = "Too many incorrect words. Check your recovery phrase again."
+					m.State = StateCreate
+					return m, nil
+				}
+				m.Input.Placeholder = fmt.Sprintf("Incorrect! Try Word #%d again", targetIndex+1)
+			}
+		}
+	}
+	return m, cmd
+}
+
+func (m Model) updatePassword(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			passphrase := m.Input.Value()
+			
+			key, err := m.Secret.PrivateKey()
+			if err != nil {
+				m.Input.Reset()
+				m.Input.Placeholder = "Error deriving keys. Try again:"
+				return m, cmd
+			}
+			
+			identifier := crypto.WalletIdentifier(key, m.Secret.KeyType)
+			
+			walletPath, err := crypto.GetWalletPath(identifier)
+			if err != nil {
+				m.ErrorMessage = fmt.Sprintf("Error getting wallet path: %v", err)
+				m.Input.Reset()
+				m.Input.Placeholder = "Error getting wallet path. Try again:"
+				return m, cmd
+			}
+			
+			pass := []byte(passphrase)
+			err = crypto.SaveWallet(m.Secret, pass, walletPath)
+			wipeBytes(pass)
+			if err != nil {
+				m.ErrorMessage = fmt.Sprintf("Failed to save wallet to %s: %v", walletPath, err)
+				m.Input.Reset()
+				m.Input.Placeholder = fmt.Sprintf("Error: %v", err)
+				return m, cmd
+			}
+			
+			if _, err := os.Stat(walletPath); err != nil {
+				m.ErrorMessage = fmt.Sprintf("Wallet file not found after save: %s (error: %v)", walletPath, err)
+				m.Input.Reset()
+				m.Input.Placeholder = "File creation failed. Try again:"
+				return m, cmd
+			}
+			
+			m.ErrorMessage = ""
+			m.Mnemonic = nil
+			m.MnemonicWords = nil
+			m.Input.Reset()
+			
+			metadata := crypto.WalletMetadata{
+				EVMAddress: crypto.CalculateEVMAddress(key, m.Secret.KeyType),
+				CreatedAt:  time.Now(),
+			}
+			metadata.SetNetwork(m.Network)
+			return m.openWallet(walletPath, metadata)
+		}
+	}
+	return m, cmd
+}
+
+// openWallet connects to the wallet's network with the unlocked m.Secret,
+// starts resolving its account and refreshes the stored metadata.
+func (m Model) openWallet(walletPath string, metadata crypto.WalletMetadata) (Model, tea.Cmd) {
+	m.SelectedWalletPath = walletPath
+	m.Network = metadata.NetworkConfig()
+	m.KeyType = m.Secret.KeyType
+	m.RefreshError = ""
+	m.State = StateDashboard
+
+	if metadata.TokenAliases != nil {
+		m.TokenAliases = metadata.TokenAliases
+	} else {
+		m.TokenAliases = make(map[string]string)
+	}
+
+	metadata.KeyType = m.KeyType
+	metadata.Derivation = m.Secret.DerivationName()
+	metadata.MnemonicPassphrase = m.Secret.HasPassphrase()
+	m.Metadata = metadata
+
+	m.HederaClient = nil
+	client, err := hedera_client.NewClient(m.Network)
+	if err != nil {
+		m.RefreshError = err.Error()
+	} else {
+		client.MaxFee = metadata.MaxFee()
+		m.HederaClient = client
+	}
+	m.PriceProvider = hedera_client.NewPriceProvider(m.HederaClient, m.Config.PriceFilePath())
+	m.Prices = nil
+
+	index := metadata.ActiveAccount
+	if !m.Secret.SupportsAccounts() {
+		index = 0
+	}
+	return m.activateAccount(index)
+}
+
+// activateAccount derives the key for an HD account index from the unlocked
+// secret and makes it the dashboard's current account. Its account ID and
+// balances are looked up in the background.
+func (m Model) activateAccount(index uint32) (Model, tea.Cmd) {
+	m.AccountID = "Unverified"
+	m.Balance = "0.00 ℏ"
+	m.BalanceTinybars = 0
+	m.TokenBalances = nil
+
+	key, err := m.Secret.PrivateKeyAt(index)
+	if err != nil {
+		m.RefreshError = fmt.Sprintf("failed to derive key: %v", err)
+		return m, nil
+	}
+	m.AccountIndex = index
+	m.PublicKey = key.PublicKey()
+	m.EVMAddress = crypto.CalculateEVMAddress(key, m.KeyType)
+	m = m.recordAccount()
+
+	if m.HederaClient == nil {
+		return m, m.fetchPrices()
+	}
+	m.IsRefreshing = true
+	return m, tea.Batch(refreshAccountCmd(m.PublicKey, m.EVMAddress, m.HederaClient), m.fetchPrices())
+}
+
+// accountRefreshed applies a lookup of the current account. A lookup for an
+// account that is no longer current is dropped.
+func (m Model) accountRefreshed(msg refreshAccountMsg) (Model, tea.Cmd) {
+	if msg.PublicKey.String() != m.PublicKey.String() {
+		return m, nil
+	}
+	m.IsRefreshing = false
+	if msg.Error != nil {
+		m.RefreshError = msg.Error.Error()
+		return m, nil
+	}
+	m.AccountID = msg.AccountID
+	if msg.EVMAddress != "" {
+		m.EVMAddress = msg.EVMAddress
+	}
+	m.Balance = msg.Balance
+	m.BalanceTinybars = msg.Tinybars
+	m.TokenBalances = msg.Tokens
+	m.RefreshError = ""
+	m = m.recordAccount()
+	return m, m.fetchPrices()
+}
+
+// recordAccount stores the current account's addresses in the wallet
+// metadata. Account 0 is also mirrored at the top level, which is what the
+// wallet list shows.
+func (m Model) recordAccount() Model {
+	account := m.Metadata.Account(m.AccountIndex)
+	account.EVMAddress = m.EVMAddress
+	if m.hasAccount() {
+		account.AccountID = m.AccountID
+	}
+	m.Metadata.ActiveAccount = m.AccountIndex
+
+	if m.AccountIndex == 0 {
+		m.Metadata.EVMAddress = m.EVMAddress
+		if m.hasAccount() {
+			m.Metadata.AccountID = m.AccountID
+		}
+	}
+
+	if m.SelectedWalletPath != "" && m.Secret != nil {
+		crypto.SaveWalletMetadata(m.SelectedWalletPath, m.Metadata, m.Secret)
+	}
+	return m
+}
+
+func (m Model) hasAccount() bool {
+	return m.AccountID != "" && m.AccountID != "Unverified" && m.AccountID != "Inactive"
+}
+
+// accountRef is the identifier used for mirror node account lookups.
+func (m Model) accountRef() string {
+	if m.hasAccount() {
+		return m.AccountID
+	}
+	return m.EVMAddress
+}
+
+func (m Model) enterPassword() Model {
+	m.State = StatePassword
+	m.Input.Reset()
+	m.Input.Placeholder = "Enter Passphrase"
+	m.Input.EchoMode = textinput.EchoPassword
+	return m
+}
+
+// networkChosen continues whichever flow asked for a network: a new wallet
+// goes on to set its passphrase, unless its key already has a wallet file,
+// and a metadata reset reopens the wallet.
+func (m Model) networkChosen() (tea.Model, tea.Cmd) {
+	if !m.ResetMetadata {
+		if walletPath, ok := m.existingWalletPath(); ok {
+			m.SelectedWalletPath = walletPath
+			m.State = StateWalletExists
+			return m, nil
+		}
+		return m.enterPassword(), nil
+	}
+
+	m.ResetMetadata = false
+	metadata := crypto.WalletMetadata{CreatedAt: time.Now()}
+	metadata.SetNetwork(m.Network)
+	return m.openWallet(m.SelectedWalletPath, metadata)
+}
+
+// existingWalletPath returns the file a new wallet would be saved to, if
+// a wallet for the same key is already there.
+func (m Model) existingWalletPath() (string, bool) {
+	key, err := m.Secret.PrivateKey()
+	if err != nil {
+		return "", false
+	}
+	walletPath, err := crypto.GetWalletPath(crypto.WalletIdentifier(key, m.Secret.KeyType))
+	if err != nil {
+		return "", false
+	}
+	if _, err := os.Stat(walletPath); err != nil {
+		return "", false
+	}
+	return walletPath, true
+}
+
+// updateWalletExists asks before a new wallet replaces the file of an
+// existing wallet for the same key, which would lose its passphrase and
+// settings.
+func (m Model) updateWalletExists(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "u":
+			m = m.discardNewWallet()
+			m.State = StateWalletUnlock
+			m.Input.Reset()
+			m.Input.Placeholder = "Enter Passphrase"
+			m.Input.EchoMode = textinput.EchoPassword
+			return m, nil
+		case "o":
+			return m.enterPassword(), nil
+		case "esc":
+			return m.discardNewWallet(), checkForWallets
+		}
+	}
+	return m, nil
+}
+
+func (m Model) discardNewWallet() Model {
+	m.Secret.Wipe()
+	m.Secret = nil
+	m.Mnemonic = nil
+	m.MnemonicWords = nil
+	return m
+}
+
+func (m Model) updateMetadataTampered(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "r":
+			m.ResetMetadata = true
+			m.State = StateNetworkSelect
+			m.NetworkCursor = 1
+			return m, nil
+		case "esc":
+			m.Secret.Wipe()
+			m.Secret = nil
+			m.State = StateWalletList
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateNetworkSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.NetworkCursor > 0 {
+				m.NetworkCursor--
+			}
+			return m, nil
+		case "down", "j":
+			if m.NetworkCursor < len(hedera_client.Networks)-1 {
+				m.NetworkCursor++
+			}
+			return m, nil
+		case "enter":
+			name := hedera_client.Networks[m.NetworkCursor]
+			m.Network = hedera_client.NetworkConfig{Name: name}
+			m.ErrorMessage = ""
+			if name == hedera_client.NetworkLocal {
+				m.State = StateNetworkCustom
+				m.CustomNetworkStep = 0
+				m.Input.Reset()
+				m.Input.EchoMode = textinput.EchoNormal
+				m.Input.Placeholder = hedera_client.DefaultLocalNodeAddress
+				return m, nil
+			}
+			return m.networkChosen()
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateNetworkCustom(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.State = StateNetworkSelect
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, nil
+		case "enter":
+			value := strings.TrimSpace(m.Input.Value())
+			switch m.CustomNetworkStep {
+			case 0:
+				if value == "" {
+					value = hedera_client.DefaultLocalNodeAddress
+				}
+				m.Network.NodeAddress = value
+				m.Input.Placeholder = hedera_client.DefaultLocalNodeAccountID
+			case 1:
+				if value == "" {
+					value = hedera_client.DefaultLocalNodeAccountID
+				}
+				if _, err := sdk.AccountIDFromString(value); err != nil {
+					m.ErrorMessage = fmt.Sprintf("Invalid node account ID: %v", err)
+					m.Input.Reset()
+					return m, nil
+				}
+				m.Network.NodeAccountID = value
+				m.Input.Placeholder = hedera_client.DefaultLocalMirrorURL
+			case 2:
+				if value == "" {
+					value = hedera_client.DefaultLocalMirrorURL
+				}
+				if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+					m.ErrorMessage = "Mirror node URL must start with http:// or https://"
+					m.Input.Reset()
+					return m, nil
+				}
+				m.Network.MirrorURL = strings.TrimRight(value, "/")
+				m.ErrorMessage = ""
+				return m.networkChosen()
+			}
+			m.ErrorMessage = ""
+			m.CustomNetworkStep++
+			m.Input.Reset()
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+func refreshAccountCmd(publicKey sdk.PublicKey, evmAddress string, client *hedera_client.Client) tea.Cmd {
+	return func() tea.Msg {
+		msg := lookupAccount(publicKey, evmAddress, client)
+		msg.PublicKey = publicKey
+		return msg
+	}
+}
+
+// lookupAccount resolves the account ID of a key and fetches its balances.
+func lookupAccount(publicKey sdk.PublicKey, evmAddress string, client *hedera_client.Client) refreshAccountMsg {
+	if client == nil {
+		return refreshAccountMsg{Error: fmt.Errorf("client not available")}
+	}
+
+	accountID, err := client.GetAccountIDForKey(publicKey, evmAddress)
+	if err != nil {
+		return refreshAccountMsg{Error: fmt.Errorf("failed to query account: %w", err)}
+	}
+
+	if accountID == "" {
+		return refreshAccountMsg{
+			AccountID: "Unverified",
+			Balance:   "0.00 ℏ",
+		}
+	}
+
+	id, err := sdk.AccountIDFromString(accountID)
+	if err != nil {
+		return refreshAccountMsg{Error: fmt.Errorf("invalid account ID: %w", err)}
+	}
+
+	info, err := client.GetAccountBalance(id)
+	if err != nil {
+		return refreshAccountMsg{
+			AccountID: accountID,
+			Balance:   "Error",
+			Error:     fmt.Errorf("failed to fetch balance: %w", err),
+		}
+	}
+
+	if evmAddress == "" {
+		evmAddress = id.ToEvmAddress()
+	}
+
+	return refreshAccountMsg{
+		AccountID:  accountID,
+		EVMAddress: evmAddress,
+		Balance:    info.Balance.String(),
+		Tinybars:   info.Balance.AsTinybar(),
+		Tokens:     info.Tokens,
+	}
+}
+
+// fetchPrices values the current account's balances.
+func (m Model) fetchPrices() tea.Cmd {
+	return fetchPricesCmd(m.PriceProvider, m.Config.Currency, m.TokenBalances)
+}
+
+type pricesMsg struct {
+	Prices map[string]*big.Rat
+}
+
+// fetchPricesCmd looks up the price of HBAR and of each fungible token.
+// Assets without a price are left out and shown without a fiat value.
+func fetchPricesCmd(provider hedera_client.PriceProvider, currency string, tokens []hedera_client.TokenBalance) tea.Cmd {
+	if provider == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		prices := make(map[string]*big.Rat)
+		tokenIDs := []string{""}
+		for _, token := range tokens {
+			if token.Info != nil && !token.Info.IsNFT() {
+				tokenIDs = append(tokenIDs, token.TokenID)
+			}
+		}
+		for _, tokenID := range tokenIDs {
+			if price, err := provider.Price(tokenID, currency); err == nil {
+				prices[tokenID] = price
+			}
+		}
+		return pricesMsg{Prices: prices}
+	}
+}
+
+func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "f":
+			if m.HederaClient != nil && !m.IsRefreshing {
+				m.IsRefreshing = true
+				m.RefreshError = ""
+				return m, refreshAccountCmd(m.PublicKey, m.EVMAddress, m.HederaClient)
+			}
+		case "s":
+			m.State = StateSendSelectToken
+			m.SelectedTokenIndex = 0
+			m.SendTokenLoading = false
+			m.SendNFT = nil
+			m.SendMemo = ""
+			m.SendError = ""
+			return m, nil
+		case "h":
+			m.State = StateHistory
+			m.StatusMessage = ""
+			// An account that is not on the ledger yet has no history.
+			m.HistoryQuery.AccountID = ""
+			if m.hasAccount() {
+				m.HistoryQuery.AccountID = m.AccountID
+			}
+			return m.reloadHistory()
+		case "r":
+			m.State = StateReceive
+			return m, nil
+		case "p":
+			if m.SelectedWalletPath != "" {
+				return m.enterChangePassphrase(m.SelectedWalletPath), nil
+			}
+			return m, nil
+		case "a":
+			return m.enterAccounts()
+		case "t":
+			return m.enterTokenMenu()
+		case "n":
+			m.StatusMessage = ""
+			return m.enterNFTs()
+		case "m":
+			m.State = StateMaxFee
+			m.ErrorMessage = ""
+			m.StatusMessage = ""
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoNormal
+			m.Input.Placeholder = "Max fee in HBAR (empty for the default)"
+			if m.Metadata.MaxFeeTinybars > 0 {
+				m.Input.SetValue(hedera_client.FormatAmount(m.Metadata.MaxFeeTinybars, hedera_client.HbarDecimals))
+			}
+			return m, nil
+		case "q":
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// updateMaxFee sets the wallet's cap on the fee of each transaction. The
+// cap is kept in the authenticated metadata, so it cannot be raised by
+// editing the file.
+func (m Model) updateMaxFee(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.State = StateDashboard
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, nil
+		case "enter":
+			var tinybars int64
+			if input := strings.TrimSpace(m.Input.Value()); input != "" {
+				var err error
+				tinybars, err = hedera_client.ParseAmount(input, hedera_client.HbarDecimals)
+				if err != nil {
+					m.ErrorMessage = err.Error()
+					return m, nil
+				}
+			}
+
+			m.Metadata.MaxFeeTinybars = tinybars
+			if m.HederaClient != nil {
+				m.HederaClient.MaxFee = m.Metadata.MaxFee()
+			}
+			if m.SelectedWalletPath != "" && m.Secret != nil {
+				if err := crypto.SaveWalletMetadata(m.SelectedWalletPath, m.Metadata, m.Secret); err != nil {
+					m.ErrorMessage = fmt.Sprintf("Failed to save the max fee: %v", err)
+					return m, nil
+				}
+			}
+
+			m.State = StateDashboard
+			m.ErrorMessage = ""
+			m.StatusMessage = fmt.Sprintf("Max fee set to %s", m.Metadata.MaxFee())
+			m.Input.Reset()
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+type accountLookup struct {
+	Index      uint32
+	PublicKey  sdk.PublicKey
+	EVMAddress string
+}
+
+type accountsResolvedMsg struct {
+	AccountIDs map[uint32]string
+}
+
+func resolveAccountsCmd(client *hedera_client.Client, lookups []accountLookup) tea.Cmd {
+	return func() tea.Msg {
+		resolved := make(map[uint32]string)
+		for _, lookup := range lookups {
+			accountID, err := client.GetAccountIDForKey(lookup.PublicKey, lookup.EVMAddress)
+			if err == nil && accountID != "" {
+				resolved[lookup.Index] = accountID
+			}
+		}
+		return accountsResolvedMsg{AccountIDs: resolved}
+	}
+}
+
+// unresolvedAccounts derives the keys of accounts whose account ID is not
+// known yet so they can be looked up on the mirror node.
+func (m Model) unresolvedAccounts() []accountLookup {
+	var lookups []accountLookup
+	for _, account := range m.Metadata.Accounts {
+		if account.AccountID != "" {
+			continue
+		}
+		key, err := m.Secret.PrivateKeyAt(account.Index)
+		if err != nil {
+			continue
+		}
+		lookups = append(lookups, accountLookup{
+			Index:      account.Index,
+			PublicKey:  key.PublicKey(),
+			EVMAddress: account.EVMAddress,
+		})
+	}
+	return lookups
+}
+
+// accountsResolved records account IDs found for the wallet's accounts.
+// Lookups can finish after the accounts screen is closed, so this runs in
+// any state.
+func (m Model) accountsResolved(msg accountsResolvedMsg) Model {
+	if len(msg.AccountIDs) == 0 || m.Secret == nil {
+		return m
+	}
+	for index, accountID := range msg.AccountIDs {
+		account := m.Metadata.Account(index)
+		account.AccountID = accountID
+		if index == 0 {
+			m.Metadata.AccountID = accountID
+		}
+	}
+	crypto.SaveWalletMetadata(m.SelectedWalletPath, m.Metadata, m.Secret)
+	return m
+}
+
+func (m Model) enterAccounts() (tea.Model, tea.Cmd) {
+	m.State = StateAccounts
+	m.EditingAccountLabel = false
+	m.ErrorMessage = ""
+	m.AccountCursor = 0
+	for i, account := range m.Metadata.Accounts {
+		if account.Index == m.AccountIndex {
+			m.AccountCursor = i
+		}
+	}
+
+	lookups := m.unresolvedAccounts()
+	if m.HederaClient == nil || len(lookups) == 0 {
+		return m, nil
+	}
+	return m, resolveAccountsCmd(m.HederaClient, lookups)
+}
+
+func (m Model) updateAccounts(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.EditingAccountLabel {
+		var cmd tea.Cmd
+		m.Input, cmd = m.Input.Update(msg)
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc":
+				m.EditingAccountLabel = false
+				m.Input.Reset()
+				return m, nil
+			case "enter":
+				m.Metadata.Accounts[m.AccountCursor].Label = strings.TrimSpace(m.Input.Value())
+				m.EditingAccountLabel = false
+				m.Input.Reset()
+				crypto.SaveWalletMetadata(m.SelectedWalletPath, m.Metadata, m.Secret)
+				return m, nil
+			}
+		}
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.State = StateDashboard
+			m.ErrorMessage = ""
+			return m, nil
+		case "up", "k":
+			if m.AccountCursor > 0 {
+				m.AccountCursor--
+			}
+			return m, nil
+		case "down", "j":
+			if m.AccountCursor < len(m.Metadata.Accounts)-1 {
+				m.AccountCursor++
+			}
+			return m, nil
+		case "enter":
+			if m.AccountCursor < len(m.Metadata.Accounts) {
+				m, cmd := m.activateAccount(m.Metadata.Accounts[m.AccountCursor].Index)
+				m.State = StateDashboard
+				return m, cmd
+			}
+			return m, nil
+		case "l":
+			if m.AccountCursor < len(m.Metadata.Accounts) {
+				m.EditingAccountLabel = true
+				m.Input.Reset()
+				m.Input.EchoMode = textinput.EchoNormal
+				m.Input.Placeholder = "Account label (e.g. Treasury)"
+				m.Input.SetValue(m.Metadata.Accounts[m.AccountCursor].Label)
+			}
+			return m, nil
+		case "n":
+			if !m.Secret.SupportsAccounts() {
+				m.ErrorMessage = "Only recovery phrase wallets with standard derivation can hold more accounts."
+				return m, nil
+			}
+			index := m.Metadata.NextAccountIndex()
+			key, err := m.Secret.PrivateKeyAt(index)
+			if err != nil {
+				m.ErrorMessage = fmt.Sprintf("Failed to derive account #%d: %v", index, err)
+				return m, nil
+			}
+			account := m.Metadata.Account(index)
+			account.EVMAddress = crypto.CalculateEVMAddress(key, m.KeyType)
+			crypto.SaveWalletMetadata(m.SelectedWalletPath, m.Metadata, m.Secret)
+			m.AccountCursor = len(m.Metadata.Accounts) - 1
+			m.ErrorMessage = ""
+
+			if m.HederaClient == nil {
+				return m, nil
+			}
+			return m, resolveAccountsCmd(m.HederaClient, []accountLookup{{
+				Index:      index,
+				PublicKey:  key.PublicKey(),
+				EVMAddress: account.EVMAddress,
+			}})
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateReceive(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "esc":
+			m.State = StateDashboard
 			return m, nil
 		case "c":
 			if m.AccountID != "" && m.AccountID != "Unverified" && m.AccountID != "Inactive" {
 				clipboard.WriteAll(m.AccountID)
 			}
-			return m, nil
-		case "e":
-			if m.EVMAddress != "" {
-				clipboard.WriteAll(m.EVMAddress)
+			return m, nil
+		case "e":
+			if m.EVMAddress != "" {
+				clipboard.WriteAll(m.EVMAddress)
+			}
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+func (m Model) enterTokenMenu() (tea.Model, tea.Cmd) {
+	m.State = StateTokenMenu
+	m.SelectedTokenIndex = 0
+	m.EditingTokenAlias = false
+	m.ErrorMessage = ""
+	m.StatusMessage = ""
+	m.Input.Reset()
+	m.Input.EchoMode = textinput.EchoNormal
+
+	if !m.hasAccount() || m.HederaClient == nil {
+		m.TokenRelationships = hedera_client.TokenRelationships{}
+		m.ErrorMessage = "This account is not on the network yet."
+		return m, nil
+	}
+	m.TokenMenuLoading = true
+	return m, fetchTokenRelationshipsCmd(m.HederaClient, m.AccountID)
+}
+
+type tokenRelationshipsMsg struct {
+	Relationships hedera_client.TokenRelationships
+	Error         error
+}
+
+func fetchTokenRelationshipsCmd(client *hedera_client.Client, accountID string) tea.Cmd {
+	return func() tea.Msg {
+		relationships, err := client.GetTokenRelationships(accountID)
+		return tokenRelationshipsMsg{Relationships: relationships, Error: err}
+	}
+}
+
+// selectedTokenRelationship returns the token under the cursor, if any.
+func (m Model) selectedTokenRelationship() (hedera_client.TokenRelationship, bool) {
+	tokens := m.TokenRelationships.Tokens
+	if m.SelectedTokenIndex < 0 || m.SelectedTokenIndex >= len(tokens) {
+		return hedera_client.TokenRelationship{}, false
+	}
+	return tokens[m.SelectedTokenIndex], true
+}
+
+func (m Model) updateTokenMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tokenRelationshipsMsg); ok {
+		m.TokenMenuLoading = false
+		if msg.Error != nil {
+			m.ErrorMessage = fmt.Sprintf("Failed to load tokens: %v", msg.Error)
+			return m, nil
+		}
+		m.TokenRelationships = msg.Relationships
+		if m.SelectedTokenIndex >= len(m.TokenRelationships.Tokens) {
+			m.SelectedTokenIndex = max(len(m.TokenRelationships.Tokens)-1, 0)
+		}
+		return m, nil
+	}
+
+	if m.EditingTokenAlias {
+		var cmd tea.Cmd
+		m.Input, cmd = m.Input.Update(msg)
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc":
+				m.EditingTokenAlias = false
+				m.Input.Reset()
+				return m, nil
+			case "enter":
+				token, ok := m.selectedTokenRelationship()
+				if !ok {
+					return m, nil
+				}
+				if m.TokenAliases == nil {
+					m.TokenAliases = make(map[string]string)
+				}
+				if alias := strings.TrimSpace(m.Input.Value()); alias != "" {
+					m.TokenAliases[token.TokenID] = alias
+				} else {
+					delete(m.TokenAliases, token.TokenID)
+				}
+				m.EditingTokenAlias = false
+				m.Input.Reset()
+
+				if m.SelectedWalletPath != "" {
+					m.Metadata.TokenAliases = m.TokenAliases
+					crypto.SaveWalletMetadata(m.SelectedWalletPath, m.Metadata, m.Secret)
+				}
+				return m, nil
+			}
+		}
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.State = StateDashboard
+			m.ErrorMessage = ""
+			m.StatusMessage = ""
+			if m.HederaClient != nil && !m.IsRefreshing {
+				m.IsRefreshing = true
+				return m, refreshAccountCmd(m.PublicKey, m.EVMAddress, m.HederaClient)
+			}
+			return m, nil
+		case "up", "k":
+			if m.SelectedTokenIndex > 0 {
+				m.SelectedTokenIndex--
+			}
+			return m, nil
+		case "down", "j":
+			if m.SelectedTokenIndex < len(m.TokenRelationships.Tokens)-1 {
+				m.SelectedTokenIndex++
+			}
+			return m, nil
+		case "l":
+			if token, ok := m.selectedTokenRelationship(); ok {
+				m.EditingTokenAlias = true
+				m.Input.Reset()
+				m.Input.Placeholder = "Enter alias for selected token..."
+				m.Input.SetValue(m.TokenAliases[token.TokenID])
+			}
+			return m, nil
+		case "f":
+			if m.hasAccount() && m.HederaClient != nil && !m.TokenMenuLoading {
+				m.TokenMenuLoading = true
+				m.ErrorMessage = ""
+				return m, fetchTokenRelationshipsCmd(m.HederaClient, m.AccountID)
+			}
+			return m, nil
+		case "a":
+			if !m.hasAccount() || m.HederaClient == nil {
+				return m, nil
+			}
+			m.State = StateTokenAssociate
+			m.ErrorMessage = ""
+			m.StatusMessage = ""
+			m.Input.Reset()
+			m.Input.Placeholder = "Token ID (e.g. 0.0.456858)"
+			return m, nil
+		case "d":
+			token, ok := m.selectedTokenRelationship()
+			if !ok {
+				return m, nil
+			}
+			m.StatusMessage = ""
+			if token.Balance != 0 {
+				m.ErrorMessage = "Only tokens with a zero balance can be dissociated. Send or burn the balance first."
+				return m, nil
+			}
+			return m.confirmTokenAction("dissociate", token.TokenID)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateTokenAssociate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.State = StateTokenMenu
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, nil
+		case "enter":
+			tokenID := strings.TrimSpace(m.Input.Value())
+			if _, err := sdk.TokenIDFromString(tokenID); err != nil {
+				m.ErrorMessage = "Invalid token ID. Use the 0.0.x format."
+				return m, nil
+			}
+			for _, token := range m.TokenRelationships.Tokens {
+				if token.TokenID == tokenID {
+					m.ErrorMessage = fmt.Sprintf("%s is already associated with this account.", tokenID)
+					return m, nil
+				}
+			}
+			return m.confirmTokenAction("associate", tokenID)
+		}
+	}
+	return m, cmd
+}
+
+type feeEstimateMsg struct {
+	Estimate hedera_client.FeeEstimate
+	Error    error
+}
+
+// estimateFee starts pricing the transaction on a confirm screen. The
+// account pays to read the network's fee schedule when none is cached.
+func (m Model) estimateFee(kind hedera_client.FeeKind) (Model, tea.Cmd) {
+	m.FeeEstimate = hedera_client.FeeEstimate{}
+	m.FeeEstimated = false
+	m.FeeError = ""
+
+	client := m.HederaClient
+	if client == nil {
+		m.FeeError = "not connected to a network"
+		return m, nil
+	}
+	payer := ""
+	var key sdk.PrivateKey
+	if m.hasAccount() && m.Secret != nil {
+		if k, err := m.Secret.PrivateKeyAt(m.AccountIndex); err == nil {
+			payer, key = m.AccountID, k
+		}
+	}
+	return m, func() tea.Msg {
+		var loadErr error
+		if payer != "" {
+			loadErr = client.LoadFeeSchedule(payer, key)
+		}
+		estimate, err := client.EstimateFee(kind)
+		if estimate.Approximate && loadErr != nil {
+			estimate.ScheduleError = loadErr
+		}
+		return feeEstimateMsg{Estimate: estimate, Error: err}
+	}
+}
+
+func (m Model) feeEstimated(msg feeEstimateMsg) Model {
+	m.FeeEstimate = msg.Estimate
+	if msg.Error != nil {
+		m.FeeError = msg.Error.Error()
+		return m
+	}
+	m.FeeEstimated = true
+	return m
+}
+
+var errFeePending = errors.New("still estimating the fee, try again in a moment")
+
+// checkFee refuses to sign before the fee is known and when it is above the
+// wallet's max fee. A failed estimate is reported so the confirm screen can
+// offer to sign anyway; only signAnyway gets past it.
+func (m Model) checkFee() error {
+	switch {
+	case m.FeeError != "":
+		return fmt.Errorf("the fee could not be estimated (%s); press A to sign anyway, the network still refuses fees above the max fee", m.FeeError)
+	case !m.FeeEstimated:
+		return errFeePending
+	}
+	return m.HederaClient.CheckFee(m.FeeEstimate.Fee)
+}
+
+// signAnyway reports whether the user may skip the fee check: only after
+// the estimate has failed.
+func (m Model) signAnyway() bool {
+	return m.FeeError != ""
+}
+
+func tokenActionFee(action string) hedera_client.FeeKind {
+	if action == "dissociate" {
+		return hedera_client.FeeTokenDissociate
+	}
+	return hedera_client.FeeTokenAssociate
+}
+
+// confirmTokenAction shows the association or dissociation for review while
+// its fee is estimated.
+func (m Model) confirmTokenAction(action, tokenID string) (tea.Model, tea.Cmd) {
+	m.State = StateTokenConfirm
+	m.TokenAction = action
+	m.TokenActionID = tokenID
+	m.ErrorMessage = ""
+	m.Input.Reset()
+	return m.estimateFee(tokenActionFee(action))
+}
+
+func (m Model) updateTokenConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case feeEstimateMsg:
+		return m.feeEstimated(msg), nil
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "esc", "n":
+			m.State = StateTokenMenu
+			return m, nil
+		case "y", "enter", "a":
+			if key := strings.ToLower(msg.String()); key != "a" || !m.signAnyway() {
+				if err := m.checkFee(); err != nil {
+					m.ErrorMessage = err.Error()
+					return m, nil
+				}
+			}
+			m.ErrorMessage = ""
+			m.State = StateTokenSigning
+			m.Input.Reset()
+			m.Input.Placeholder = "Enter Wallet Passphrase to Sign"
+			m.Input.EchoMode = textinput.EchoPassword
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+type tokenActionResultMsg struct {
+	TransactionID string
+	Error         error
+}
+
+func (m Model) updateTokenSigning(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.State = StateTokenConfirm
+			m.Input.Reset()
+			m.Input.EchoMode = textinput.EchoNormal
+			return m, nil
+		case "enter":
+			passphrase := m.Input.Value()
+			if passphrase == "" {
+				return m, nil
+			}
+
+			pass := []byte(passphrase)
+			secret, err := crypto.LoadWallet(pass, m.SelectedWalletPath)
+			wipeBytes(pass)
+			m.Input.Reset()
+			if err != nil {
+				m.ErrorMessage = "Invalid passphrase"
+				return m, nil
+			}
+
+			key, err := secret.PrivateKeyAt(m.AccountIndex)
+			secret.Wipe()
+			if err != nil {
+				m.ErrorMessage = "Failed to derive key"
+				return m, nil
+			}
+
+			m.ErrorMessage = ""
+			m.Input.EchoMode = textinput.EchoNormal
+			client, action, accountID, tokenID := m.HederaClient, m.TokenAction, m.AccountID, m.TokenActionID
+			return m, func() tea.Msg {
+				var txID string
+				var err error
+				if action == "associate" {
+					txID, err = client.AssociateToken(accountID, tokenID, key)
+				} else {
+					txID, err = client.DissociateToken(accountID, tokenID, key)
+				}
+				return tokenActionResultMsg{TransactionID: txID, Error: err}
+			}
+		}
+	case tokenActionResultMsg:
+		if msg.Error != nil {
+			m.ErrorMessage = msg.Error.Error()
+			m.State = StateTokenConfirm
+			return m, nil
+		}
+		verb := "Associated"
+		if m.TokenAction == "dissociate" {
+			verb = "Dissociated"
+		}
+		m.State = StateTokenMenu
+		m.StatusMessage = fmt.Sprintf("%s %s. Transaction ID: %s", verb, m.TokenActionID, msg.TransactionID)
+		m.TokenMenuLoading = true
+		return m, fetchTokenRelationshipsCmd(m.HederaClient, m.AccountID)
+	}
+	return m, cmd
+}
+
+func (m Model) enterNFTs() (tea.Model, tea.Cmd) {
+	m.State = StateNFTs
+	m.NFTCursor = 0
+	m.ErrorMessage = ""
+
+	if !m.hasAccount() || m.HederaClient == nil {
+		m.NFTs = nil
+		m.ErrorMessage = "This account is not on the network yet."
+		return m, nil
+	}
+	m.NFTsLoading = true
+	return m, fetchNFTsCmd(m.HederaClient, m.AccountID)
+}
+
+type nftsMsg struct {
+	NFTs  []hedera_client.NFT
+	Error error
+}
+
+func fetchNFTsCmd(client *hedera_client.Client, accountID string) tea.Cmd {
+	return func() tea.Msg {
+		nfts, err := client.GetNFTs(accountID)
+		return nftsMsg{NFTs: nfts, Error: err}
+	}
+}
+
+func (m Model) updateNFTs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case nftsMsg:
+		m.NFTsLoading = false
+		if msg.Error != nil {
+			m.ErrorMessage = fmt.Sprintf("Failed to load NFTs: %v", msg.Error)
+			return m, nil
+		}
+		m.NFTs = msg.NFTs
+		if m.NFTCursor >= len(m.NFTs) {
+			m.NFTCursor = max(len(m.NFTs)-1, 0)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.State = StateDashboard
+			m.StatusMessage = ""
+			return m, nil
+		case "up", "k":
+			if m.NFTCursor > 0 {
+				m.NFTCursor--
+			}
+		case "down", "j":
+			if m.NFTCursor < len(m.NFTs)-1 {
+				m.NFTCursor++
+			}
+		case "f":
+			if !m.NFTsLoading {
+				m.StatusMessage = ""
+				return m.enterNFTs()
+			}
+		case "enter":
+			if m.NFTCursor < len(m.NFTs) {
+				m.State = StateNFTDetail
+			}
+		case "s":
+			if m.NFTCursor < len(m.NFTs) {
+				return m.startNFTSend(m.NFTs[m.NFTCursor]), nil
 			}
-			return m, nil
 		}
 	}
 	return m, nil
 }
 
-func (m Model) updateTokenMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	m.Input, cmd = m.Input.Update(msg)
-
+func (m Model) updateNFTDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
-			m.State = StateDashboard
-			return m, nil
-		case "up", "k":
-			if m.SelectedTokenIndex > 0 {
-				m.SelectedTokenIndex--
-			}
-			return m, nil
-		case "down", "j":
-			if m.SelectedTokenIndex < len(m.TokenBalances)-1 {
-				m.SelectedTokenIndex++
-			}
-			return m, nil
-		case "enter":
-			alias := m.Input.Value()
-			if alias != "" {
-				if m.TokenAliases == nil {
-					m.TokenAliases = make(map[string]string)
-				}
-				tokenID := m.TokenBalances[m.SelectedTokenIndex].TokenID
-				m.TokenAliases[tokenID] = alias
-				m.Input.Reset()
-				
-				if m.SelectedWalletPath != "" {
-					metadata, err := crypto.LoadWalletMetadata(m.SelectedWalletPath)
-					if err == nil {
-						metadata.TokenAliases = m.TokenAliases
-						crypto.SaveWalletMetadata(m.SelectedWalletPath, metadata)
-					}
-				}
-			}
-			return m, nil
+			m.State = StateNFTs
+		case "s":
+			return m.startNFTSend(m.NFTs[m.NFTCursor]), nil
 		}
 	}
-	return m, cmd
+	return m, nil
+}
+
+// startNFTSend enters the send flow for a single NFT. The amount step is
+// skipped, since a serial is always sent whole.
+func (m Model) startNFTSend(nft hedera_client.NFT) Model {
+	m.SendNFT = &nft
+	m.SendSelectedToken = hedera_client.TokenBalance{TokenID: nft.TokenID, Balance: 1, Info: nft.Collection}
+	m.SendAmount = 1
+	m.SendMemo = ""
+	m.SendError = ""
+	m.StatusMessage = ""
+	m.State = StateSendRecipient
+	m.Input.Reset()
+	m.Input.Placeholder = "Enter Recipient (Account ID or EVM Address)"
+	m.Input.EchoMode = textinput.EchoNormal
+	return m
+}
+
+type sendTokenInfoMsg struct {
+	Token hedera_client.TokenBalance
+	Error error
+}
+
+func fetchSendTokenInfoCmd(client *hedera_client.Client, token hedera_client.TokenBalance) tea.Cmd {
+	return func() tea.Msg {
+		info, err := client.GetTokenInfo(token.TokenID)
+		if err != nil {
+			return sendTokenInfoMsg{Token: token, Error: err}
+		}
+		token.Info = &info
+		return sendTokenInfoMsg{Token: token}
+	}
 }
 
 func (m Model) updateSendSelectToken(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case sendTokenInfoMsg:
+		if !m.SendTokenLoading {
+			return m, nil
+		}
+		m.SendTokenLoading = false
+		if msg.Error != nil {
+			m.SendError = fmt.Sprintf("Failed to look up token %s: %v", msg.Token.TokenID, msg.Error)
+			return m, nil
+		}
+		for i, token := range m.TokenBalances {
+			if token.TokenID == msg.Token.TokenID {
+				m.TokenBalances[i].Info = msg.Token.Info
+			}
+		}
+		return m.sendTokenChosen(msg.Token)
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
 			m.State = StateDashboard
+			m.SendTokenLoading = false
 			return m, nil
 		case "up", "k":
-			if m.SelectedTokenIndex > 0 {
+			if m.SelectedTokenIndex > 0 && !m.SendTokenLoading {
 				m.SelectedTokenIndex--
 			}
 			return m, nil
 		case "down", "j":
-			if m.SelectedTokenIndex < len(m.TokenBalances) {
+			if m.SelectedTokenIndex < len(m.TokenBalances) && !m.SendTokenLoading {
 				m.SelectedTokenIndex++
 			}
 			return m, nil
 		case "enter":
+			if m.SendTokenLoading {
+				return m, nil
+			}
 			if m.SelectedTokenIndex == 0 {
-				m.SendSelectedToken = hedera_client.TokenBalance{TokenID: ""}
-			} else {
-				m.SendSelectedToken = m.TokenBalances[m.SelectedTokenIndex-1]
+				return m.sendTokenChosen(hedera_client.TokenBalance{TokenID: ""})
 			}
-			
-			m.State = StateSendRecipient
-			m.Input.Reset()
-			m.Input.Placeholder = "Enter Recipient (Account ID or EVM Address)"
-			m.Input.EchoMode = textinput.EchoNormal
-			m.SendError = ""
-			return m, nil
+			// Amounts can only be converted once the token's decimals are
+			// known.
+			token := m.TokenBalances[m.SelectedTokenIndex-1]
+			if token.Info == nil {
+				m.SendTokenLoading = true
+				m.SendError = ""
+				return m, fetchSendTokenInfoCmd(m.HederaClient, token)
+			}
+			return m.sendTokenChosen(token)
 		}
 	}
 	return m, nil
 }
 
+// sendTokenChosen moves on from the asset list once the token's info is
+// known. An NFT collection continues with the choice of NFT.
+func (m Model) sendTokenChosen(token hedera_client.TokenBalance) (tea.Model, tea.Cmd) {
+	if token.Info != nil && token.Info.IsNFT() {
+		m.StatusMessage = "Choose the NFT to send."
+		return m.enterNFTs()
+	}
+	m.SendSelectedToken = token
+
+	m.State = StateSendRecipient
+	m.Input.Reset()
+	m.Input.Placeholder = "Enter Recipient (Account ID or EVM Address)"
+	m.Input.EchoMode = textinput.EchoNormal
+	m.SendError = ""
+	return m, nil
+}
+
 func (m Model) updateSendRecipient(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.Input, cmd = m.Input.Update(msg)
@@ -512,6 +2106,9 @@ func (m Model) updateSendRecipient(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "esc":
 			m.State = StateSendSelectToken
+			if m.SendNFT != nil {
+				m.State = StateNFTs
+			}
 			return m, nil
 		case "enter":
 			recipient := strings.TrimSpace(m.Input.Value())
@@ -525,10 +2122,7 @@ func (m Model) updateSendRecipient(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			
 			m.SendRecipient = recipient
-			m.State = StateSendAmount
-			m.Input.Reset()
-			m.Input.Placeholder = "Enter Amount"
-			return m, nil
+			return m.recipientChosen(), nil
 		}
 	case recipientResolvedMsg:
 		if msg.Error != nil {
@@ -537,16 +2131,26 @@ func (m Model) updateSendRecipient(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Input.Reset()
 		} else {
 			m.SendRecipient = msg.AccountID
-			m.State = StateSendAmount
-			m.Input.Reset()
-			m.Input.Placeholder = "Enter Amount"
 			m.SendError = ""
+			m = m.recipientChosen()
 		}
 		return m, nil
 	}
 	return m, cmd
 }
 
+// recipientChosen moves on to the amount, or straight to the memo when an
+// NFT is being sent.
+func (m Model) recipientChosen() Model {
+	if m.SendNFT != nil {
+		return m.enterSendMemo()
+	}
+	m.State = StateSendAmount
+	m.Input.Reset()
+	m.Input.Placeholder = "Enter Amount"
+	return m
+}
+
 type recipientResolvedMsg struct {
 	AccountID string
 	Error     error
@@ -576,13 +2180,66 @@ func (m Model) updateSendAmount(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.State = StateSendRecipient
 			return m, nil
 		case "enter":
-			amount := strings.TrimSpace(m.Input.Value())
-			if amount == "" {
+			input := strings.TrimSpace(m.Input.Value())
+			if input == "" {
+				return m, nil
+			}
+			amount, err := hedera_client.ParseAmount(input, m.sendDecimals())
+			if err != nil {
+				m.SendError = err.Error()
 				return m, nil
 			}
 			m.SendAmount = amount
-			m.State = StateSendConfirm
+			m.SendError = ""
+			return m.enterSendMemo(), nil
+		}
+	}
+	return m, cmd
+}
+
+func (m Model) enterSendMemo() Model {
+	m.State = StateSendMemo
+	m.SendMemoWarned = false
+	m.Input.Reset()
+	m.Input.SetValue(m.SendMemo)
+	m.Input.Placeholder = "Memo (optional)"
+	return m
+}
+
+func (m Model) updateSendMemo(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.SendError = ""
+			if m.SendNFT != nil {
+				m.State = StateSendRecipient
+				m.Input.SetValue(m.SendRecipient)
+				return m, nil
+			}
+			m.State = StateSendAmount
+			m.Input.SetValue(hedera_client.FormatAmount(m.SendAmount, m.sendDecimals()))
 			return m, nil
+		case "enter":
+			memo := m.Input.Value()
+			if err := hedera_client.ValidateMemo(memo); err != nil {
+				m.SendError = err.Error()
+				return m, nil
+			}
+			// A deposit to an exchange without its memo is usually not
+			// credited, so the first attempt only warns.
+			if memo == "" && m.Config.RequiresMemo(m.SendRecipient) && !m.SendMemoWarned {
+				m.SendMemoWarned = true
+				m.SendError = fmt.Sprintf("%s requires a memo. Press Enter again to send without one.", m.SendRecipient)
+				return m, nil
+			}
+			m.SendMemo = memo
+			m.SendError = ""
+			m.State = StateSendConfirm
+			return m.estimateFee(hedera_client.TransferFeeKind(m.SendSelectedToken.TokenID, m.SendNFT != nil))
 		}
 	}
 	return m, cmd
@@ -590,12 +2247,20 @@ func (m Model) updateSendAmount(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) updateSendConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case feeEstimateMsg:
+		return m.feeEstimated(msg), nil
 	case tea.KeyMsg:
 		switch strings.ToLower(msg.String()) {
 		case "esc":
-			m.State = StateSendAmount
-			return m, nil
-		case "y", "enter":
+			return m.enterSendMemo(), nil
+		case "y", "enter", "a":
+			if key := strings.ToLower(msg.String()); key != "a" || !m.signAnyway() {
+				if err := m.checkFee(); err != nil {
+					m.SendError = err.Error()
+					return m, nil
+				}
+			}
+			m.SendError = ""
 			m.State = StateSendSigning
 			m.Input.Reset()
 			m.Input.Placeholder = "Enter Wallet Passphrase to Sign"
@@ -624,21 +2289,23 @@ func (m Model) updateSendSigning(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			
-			mnemonic, err := crypto.LoadWallet(passphrase, m.SelectedWalletPath)
+			pass := []byte(passphrase)
+			secret, err := crypto.LoadWallet(pass, m.SelectedWalletPath)
+			wipeBytes(pass)
 			if err != nil {
 				m.SendError = "Invalid passphrase"
 				m.Input.Reset()
 				return m, nil
 			}
 			
-			ecdsaKey, err := crypto.DeriveECDSAKey(mnemonic)
+			key, err := secret.PrivateKeyAt(m.AccountIndex)
+			secret.Wipe()
 			if err != nil {
 				m.SendError = "Failed to derive key"
 				return m, nil
 			}
-			privateKey := ecdsaKey.String()
 			
-			return m, sendTransactionCmd(m.HederaClient, m.AccountID, m.SendRecipient, m.SendSelectedToken, m.SendAmount, privateKey)
+			return m, sendTransactionCmd(m.HederaClient, m.AccountID, m.SendRecipient, m.SendSelectedToken, m.SendAmount, m.SendMemo, m.SendNFT, key)
 		}
 	case transactionResultMsg:
 		if msg.Error != nil {
@@ -646,8 +2313,9 @@ func (m Model) updateSendSigning(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.State = StateSendConfirm
 		} else {
 			m.SendSuccess = fmt.Sprintf("Transaction Sent! ID: %s", msg.TransactionID)
+			m.SendNFT = nil
 			m.State = StateDashboard
-			return m, refreshAccountCmd(m.EVMAddress, m.HederaClient)
+			return m, refreshAccountCmd(m.PublicKey, m.EVMAddress, m.HederaClient)
 		}
 		return m, nil
 	}
@@ -659,19 +2327,24 @@ type transactionResultMsg struct {
 	Error         error
 }
 
-func sendTransactionCmd(client *hedera_client.Client, senderID, recipientID string, token hedera_client.TokenBalance, amountStr string, privateKey string) tea.Cmd {
-	return func() tea.Msg {
-		var amount float64
-		_, err := fmt.Sscanf(amountStr, "%f", &amount)
-		if err != nil {
-			return transactionResultMsg{Error: fmt.Errorf("invalid amount format")}
-		}
+// sendDecimals is how many decimal places the asset being sent has.
+func (m Model) sendDecimals() int {
+	if m.SendSelectedToken.TokenID == "" {
+		return hedera_client.HbarDecimals
+	}
+	return m.SendSelectedToken.Info.Decimals
+}
 
+func sendTransactionCmd(client *hedera_client.Client, senderID, recipientID string, token hedera_client.TokenBalance, amount int64, memo string, nft *hedera_client.NFT, key sdk.PrivateKey) tea.Cmd {
+	return func() tea.Msg {
 		var txID string
-		if token.TokenID == "" {
-			txID, err = client.TransferHbar(senderID, recipientID, amount, privateKey)
+		var err error
+		if nft != nil {
+			txID, err = client.TransferNFT(senderID, recipientID, nft.TokenID, nft.SerialNumber, memo, key)
+		} else if token.TokenID == "" {
+			txID, err = client.TransferHbar(senderID, recipientID, amount, memo, key)
 		} else {
-			txID, err = client.TransferToken(senderID, recipientID, token.TokenID, amount, 0, privateKey)
+			txID, err = client.TransferToken(senderID, recipientID, token.TokenID, amount, memo, key)
 		}
 
 		if err != nil {
@@ -688,11 +2361,47 @@ func (m Model) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "esc", "q":
 			m.State = StateDashboard
+			m.StatusMessage = ""
 			return m, nil
+		case "up", "k":
+			if m.HistoryCursor > 0 {
+				m.HistoryCursor--
+			}
+		case "down", "j":
+			if m.HistoryCursor < len(m.HistoryTransactions)-1 {
+				m.HistoryCursor++
+			}
+		case "enter":
+			if !m.HistoryIsLoading && m.HistoryCursor < len(m.HistoryTransactions) {
+				m.State = StateHistoryDetail
+				m.ErrorMessage = ""
+				m.StatusMessage = ""
+			}
 		case "n":
 			if m.HistoryNextURL != "" && !m.HistoryIsLoading {
+				m.HistoryPrevURLs = append(m.HistoryPrevURLs, m.HistoryPageURL)
+				m.HistoryPageURL = m.HistoryNextURL
 				m.HistoryIsLoading = true
-				return m, fetchHistoryCmd(m.EVMAddress, m.HistoryNextURL, m.HederaClient)
+				return m, fetchHistoryCmd(m.HederaClient, m.HistoryQuery, m.HistoryPageURL)
+			}
+		case "p":
+			if len(m.HistoryPrevURLs) > 0 && !m.HistoryIsLoading {
+				last := len(m.HistoryPrevURLs) - 1
+				m.HistoryPageURL = m.HistoryPrevURLs[last]
+				m.HistoryPrevURLs = m.HistoryPrevURLs[:last]
+				m.HistoryIsLoading = true
+				return m, fetchHistoryCmd(m.HederaClient, m.HistoryQuery, m.HistoryPageURL)
+			}
+		case "e":
+			if m.HistoryQuery.AccountID != "" {
+				return m.enterHistoryExport()
+			}
+		case "f":
+			if !m.HistoryIsLoading {
+				m.State = StateHistoryFilter
+				m.HistoryFilterCursor = 0
+				m.HistoryFilterEditing = false
+				m.ErrorMessage = ""
 			}
 		}
 	case historyFetchedMsg:
@@ -702,6 +2411,7 @@ func (m Model) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.HistoryTransactions = msg.Transactions
 			m.HistoryNextURL = msg.NextURL
+			m.HistoryCursor = 0
 			m.HistoryError = ""
 		}
 		return m, nil
@@ -709,27 +2419,332 @@ func (m Model) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) updateHistoryDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "esc", "q":
+			m.State = StateHistory
+			m.ErrorMessage = ""
+			m.StatusMessage = ""
+			return m, nil
+		case "c":
+			tx := m.HistoryTransactions[m.HistoryCursor]
+			if err := clipboard.WriteAll(tx.TransactionID); err != nil {
+				m.ErrorMessage = fmt.Sprintf("Failed to copy: %v", err)
+				m.StatusMessage = ""
+			} else {
+				m.ErrorMessage = ""
+				m.StatusMessage = "Transaction ID copied to the clipboard"
+			}
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// reloadHistory fetches the first page of history for the current query.
+func (m Model) reloadHistory() (tea.Model, tea.Cmd) {
+	m.HistoryIsLoading = true
+	m.HistoryError = ""
+	m.HistoryPageURL = ""
+	m.HistoryPrevURLs = nil
+	return m, fetchHistoryCmd(m.HederaClient, m.HistoryQuery, "")
+}
+
+// History filter fields, in screen order.
+const (
+	historyFilterType = iota
+	historyFilterResult
+	historyFilterDirection
+	historyFilterToken
+	historyFilterSince
+	historyFilterUntil
+	historyFilterLimit
+	historyFilterCount
+)
+
+var (
+	historyResults    = []string{"", hedera_client.ResultSuccess, hedera_client.ResultFail}
+	historyDirections = []string{"", hedera_client.DirectionIn, hedera_client.DirectionOut}
+	historyLimits     = []int{10, hedera_client.DefaultHistoryLimit, 50, hedera_client.MaxHistoryLimit}
+)
+
+// cycle steps through options from current, wrapping at either end.
+func cycle[T comparable](options []T, current T, step int) T {
+	index := 0
+	for i, option := range options {
+		if option == current {
+			index = i
+		}
+	}
+	return options[(index+step+len(options))%len(options)]
+}
+
+func (m Model) updateHistoryFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.HistoryFilterEditing {
+		return m.updateHistoryFilterInput(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	step := 1
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.HistoryFilterCursor > 0 {
+			m.HistoryFilterCursor--
+		}
+		return m, nil
+	case "down", "j":
+		if m.HistoryFilterCursor < historyFilterCount-1 {
+			m.HistoryFilterCursor++
+		}
+		return m, nil
+	case "x":
+		m.setHistoryFilter(m.HistoryFilterCursor, "")
+		return m, nil
+	case "c":
+		for field := 0; field < historyFilterCount; field++ {
+			m.setHistoryFilter(field, "")
+		}
+		return m, nil
+	case "esc":
+		since, until, err := hedera_client.ParseHistoryRange(m.HistorySince, m.HistoryUntil)
+		if err != nil {
+			m.ErrorMessage = err.Error()
+			return m, nil
+		}
+		m.HistoryQuery.Since = since
+		m.HistoryQuery.Until = until
+		m.State = StateHistory
+		m.ErrorMessage = ""
+		return m.reloadHistory()
+	case "left", "h":
+		step = -1
+	case "right", "l", "enter", " ":
+	default:
+		return m, nil
+	}
+
+	q := &m.HistoryQuery
+	switch m.HistoryFilterCursor {
+	case historyFilterType:
+		q.Type = cycle(append([]string{""}, hedera_client.HistoryTypes...), q.Type, step)
+	case historyFilterResult:
+		q.Result = cycle(historyResults, q.Result, step)
+	case historyFilterDirection:
+		q.Direction = cycle(historyDirections, q.Direction, step)
+	case historyFilterLimit:
+		if q.Limit == 0 {
+			q.Limit = hedera_client.DefaultHistoryLimit
+		}
+		q.Limit = cycle(historyLimits, q.Limit, step)
+	default:
+		// Text fields are edited with Enter.
+		if keyMsg.String() != "enter" {
+			return m, nil
+		}
+		m.HistoryFilterEditing = true
+		m.ErrorMessage = ""
+		m.Input.Reset()
+		m.Input.SetValue(m.historyFilterText(m.HistoryFilterCursor))
+		m.Input.CursorEnd()
+		m.Input.Focus()
+		switch m.HistoryFilterCursor {
+		case historyFilterToken:
+			m.Input.Placeholder = "0.0.12345"
+		default:
+			m.Input.Placeholder = "YYYY-MM-DD"
+		}
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+func (m Model) updateHistoryFilterInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.HistoryFilterEditing = false
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, nil
+		case "enter":
+			value := strings.TrimSpace(m.Input.Value())
+			switch m.HistoryFilterCursor {
+			case historyFilterToken:
+				if value != "" {
+					if _, err := sdk.TokenIDFromString(value); err != nil {
+						m.ErrorMessage = fmt.Sprintf("Invalid token ID: %v", err)
+						return m, nil
+					}
+				}
+			case historyFilterSince, historyFilterUntil:
+				if _, _, err := hedera_client.ParseHistoryRange(value, ""); err != nil {
+					m.ErrorMessage = err.Error()
+					return m, nil
+				}
+			}
+			m.setHistoryFilter(m.HistoryFilterCursor, value)
+			m.HistoryFilterEditing = false
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, nil
+		}
+	}
+	return m, cmd
+}
+
+// setHistoryFilter sets a text field, or clears any field when value is
+// empty.
+func (m *Model) setHistoryFilter(field int, value string) {
+	switch field {
+	case historyFilterType:
+		m.HistoryQuery.Type = value
+	case historyFilterResult:
+		m.HistoryQuery.Result = value
+	case historyFilterDirection:
+		m.HistoryQuery.Direction = value
+	case historyFilterToken:
+		m.HistoryQuery.TokenID = value
+	case historyFilterSince:
+		m.HistorySince = value
+	case historyFilterUntil:
+		m.HistoryUntil = value
+	case historyFilterLimit:
+		m.HistoryQuery.Limit = 0
+	}
+}
+
+func (m Model) historyFilterText(field int) string {
+	switch field {
+	case historyFilterToken:
+		return m.HistoryQuery.TokenID
+	case historyFilterSince:
+		return m.HistorySince
+	case historyFilterUntil:
+		return m.HistoryUntil
+	}
+	return ""
+}
+
+func (m Model) enterHistoryExport() (tea.Model, tea.Cmd) {
+	m.State = StateHistoryExport
+	if m.HistoryExportFormat == "" {
+		m.HistoryExportFormat = hedera_client.ExportCSV
+	}
+	m.ErrorMessage = ""
+	m.StatusMessage = ""
+	m.Input.Reset()
+	m.Input.Placeholder = "File name"
+	m.Input.SetValue(fmt.Sprintf("shred-%s-%s.%s", m.HistoryQuery.AccountID, time.Now().Format("2006-01-02"), m.HistoryExportFormat))
+	m.Input.CursorEnd()
+	m.Input.Focus()
+	return m, textinput.Blink
+}
+
+func (m Model) updateHistoryExport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case historyExportedMsg:
+		m.HistoryExporting = false
+		if msg.Error != nil {
+			m.ErrorMessage = fmt.Sprintf("Export failed: %v", msg.Error)
+			return m, nil
+		}
+		m.State = StateHistory
+		m.ErrorMessage = ""
+		m.StatusMessage = fmt.Sprintf("Exported %d entries to %s", msg.Entries, msg.Path)
+		m.Input.Reset()
+		return m, nil
+	case tea.KeyMsg:
+		if m.HistoryExporting {
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			m.State = StateHistory
+			m.ErrorMessage = ""
+			m.Input.Reset()
+			return m, nil
+		case "tab":
+			// Keep the file extension in step with the format.
+			previous := m.HistoryExportFormat
+			m.HistoryExportFormat = cycle(hedera_client.ExportFormats, previous, 1)
+			if path, ok := strings.CutSuffix(m.Input.Value(), "."+previous); ok {
+				m.Input.SetValue(path + "." + m.HistoryExportFormat)
+				m.Input.CursorEnd()
+			}
+			return m, nil
+		case "enter":
+			path := strings.TrimSpace(m.Input.Value())
+			if path == "" {
+				m.ErrorMessage = "Enter a file name"
+				return m, nil
+			}
+			m.HistoryExporting = true
+			m.ErrorMessage = ""
+			return m, exportHistoryCmd(m.HederaClient, m.HistoryQuery, m.HistoryExportFormat, path)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+type historyExportedMsg struct {
+	Path    string
+	Entries int
+	Error   error
+}
+
+// exportHistoryCmd writes every transaction matching query to path. Only
+// the page size of query is ignored.
+func exportHistoryCmd(client *hedera_client.Client, query hedera_client.HistoryQuery, format, path string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := client.LedgerEntries(query)
+		if err != nil {
+			return historyExportedMsg{Error: err}
+		}
+
+		var ledger bytes.Buffer
+		if err := hedera_client.WriteLedger(&ledger, format, query.AccountID, entries, query.Since, query.Until); err != nil {
+			return historyExportedMsg{Error: err}
+		}
+		if err := os.WriteFile(path, ledger.Bytes(), 0600); err != nil {
+			return historyExportedMsg{Error: err}
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return historyExportedMsg{Path: path, Entries: len(entries)}
+	}
+}
+
 type historyFetchedMsg struct {
 	Transactions []hedera_client.MirrorTransaction
 	NextURL      string
 	Error        error
 }
 
-func fetchHistoryCmd(evmAddress string, url string, client *hedera_client.Client) tea.Cmd {
+func fetchHistoryCmd(client *hedera_client.Client, query hedera_client.HistoryQuery, pageURL string) tea.Cmd {
 	return func() tea.Msg {
-		info, err := client.GetAccountInfoWithTransactions(evmAddress, url)
+		if query.AccountID == "" {
+			return historyFetchedMsg{}
+		}
+		page, err := client.GetTransactions(query, pageURL)
 		if err != nil {
 			return historyFetchedMsg{Error: err}
 		}
-		
-		next := ""
-		if info.Links.Next != "" {
-			next = info.Links.Next
-		}
-		
 		return historyFetchedMsg{
-			Transactions: info.Transactions,
-			NextURL:      next,
+			Transactions: page.Transactions,
+			NextURL:      page.Next,
 		}
 	}
 }
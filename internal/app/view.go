@@ -2,11 +2,14 @@ package app
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/divin3circle/shred/internal/crypto"
+	hedera_client "github.com/divin3circle/shred/internal/hedera"
 	"github.com/mdp/qrterminal/v3"
 )
 
@@ -38,6 +41,8 @@ No wallet found. Create or import?
 
 [N] Create new wallet
 [I] Import seed phrase
+[P] Import private key
+[R] Recover from Shamir shares
 [Q] Quit
 `, GetStyledLogo())
 
@@ -56,6 +61,9 @@ func (m Model) viewWalletList() string {
 No wallets found.
 
 [N] Create new wallet
+[I] Import seed phrase
+[P] Import private key
+[R] Recover from Shamir shares
 [Q] Quit
 `, GetStyledLogo())
 		boxedContent := styleBox.Render(content)
@@ -84,23 +92,29 @@ No wallets found.
 
 		walletList.WriteString(fmt.Sprintf("%s[%d]\nWallet Name:%s\n", cursor, i+1, walletFileName))
 		walletList.WriteString(fmt.Sprintf("Wallet Address: 0x%s\n", evmDisplay))
+		walletList.WriteString(fmt.Sprintf("Network: %s\n", hedera_client.NetworkConfig{Name: wallet.Network}.DisplayName()))
 		walletList.WriteString(fmt.Sprintf("Status: %s\n\n", status))
 	}
 
 	currentDate := time.Now().Format(time.UnixDate)
 
-	footer := fmt.Sprintf("%s \t Network: Testnet", currentDate)
+	network := hedera_client.NetworkConfig{Name: hedera_client.NetworkTestnet}
+	if m.SelectedWalletIndex < len(m.AvailableWallets) {
+		network.Name = m.AvailableWallets[m.SelectedWalletIndex].Network
+	}
+	footer := fmt.Sprintf("%s \t Network: %s", currentDate, network.DisplayName())
 
 	content := fmt.Sprintf(`
 %s
 
-[↑↓] Navigate  [Enter] Select  [N] New Wallet  [Q] Quit
-
+[↑↓] Navigate  [Enter] Select  [N] New Wallet  [I] Import  [P] Import Key  [R] Recover Shares
+[C] Change Passphrase  [Q] Quit
+%s
 
 
 %s
 
-`, walletList.String(), taglineStyle.Render(footer))
+`, walletList.String(), m.noticeLine(), taglineStyle.Render(footer))
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
@@ -134,6 +148,45 @@ Enter passphrase:
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
+func (m Model) viewMetadataTampered() string {
+	content := fmt.Sprintf(`
+%s
+
+⚠️  The metadata file for this wallet failed its integrity check.
+
+It was changed or removed outside shred, so its account ID, network
+and token aliases cannot be trusted and have not been loaded.
+
+File: %s
+
+Resetting discards the stored metadata. You will choose the network
+again and the account is looked up from your key.
+
+[R] Reset metadata  [Esc] Cancel
+`, styleTitle.Render("Metadata Tampered"), crypto.GetMetadataPath(m.SelectedWalletPath))
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewWalletExists() string {
+	content := fmt.Sprintf(`
+%s
+
+⚠️  A wallet for this key already exists.
+
+File: %s
+
+Saving the new wallet replaces it: its passphrase, network, account
+labels and token aliases are lost. The key itself is unchanged.
+
+[U] Unlock existing wallet  [O] Overwrite  [Esc] Cancel
+`, styleTitle.Render("Wallet Exists"), m.SelectedWalletPath)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
 func (m Model) viewCreate() string {
 	var wordsView strings.Builder
 	for i, word := range m.MnemonicWords {
@@ -150,305 +203,1037 @@ Write down these 24 words. They are the ONLY way to recover your funds.
 
 %s
 
-[Enter] I have written them down
-`, styleTitle.Render("Secret Recovery Phrase"), wordsView.String())
+%s
+[Enter] I have written them down  [S] Split into Shamir shares instead
+`, styleTitle.Render("Secret Recovery Phrase"), wordsView.String(), m.noticeLine())
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewVerify() string {
-	targetIndex := m.VerifyIndices[m.CurrentVerifyIndex]
+func (m Model) viewImport() string {
+	var wordsView strings.Builder
+	for i, word := range m.ImportWords {
+		wordsView.WriteString(fmt.Sprintf("%2d. %-10s ", i+1, word))
+		if (i+1)%4 == 0 {
+			wordsView.WriteString("\n")
+		}
+	}
+
+	suggestions := crypto.SuggestMnemonicWords(strings.TrimSpace(strings.ToLower(m.Input.Value())), 6)
+	suggestionLine := ""
+	if len(suggestions) > 0 {
+		suggestionLine = "Suggestions: " + strings.Join(suggestions, "  ")
+	}
+
+	errorMsg := ""
+	if m.ErrorMessage != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.ErrorMessage)
+	}
+
 	content := fmt.Sprintf(`
 %s
 
-Verify your recovery phrase.
-Enter word #%d:
+Enter your 12, 15, 18, 21 or 24 word recovery phrase, one word at a time.
+
+%s
 
 %s
-`, styleTitle.Render("Verify Phrase"), targetIndex+1, m.Input.View())
+%s
+%s
+[Tab] Complete  [Enter] Add word (empty to finish)  [Backspace] Remove last  [Esc] Cancel
+`, styleTitle.Render("Import Recovery Phrase"), wordsView.String(), m.Input.View(), taglineStyle.Render(suggestionLine), errorMsg)
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewPassword() string {
+func (m Model) viewImportKey() string {
 	errorMsg := ""
 	if m.ErrorMessage != "" {
 		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.ErrorMessage)
 	}
 
+	keyType := crypto.KeyTypes[m.KeyTypeCursor]
+	if m.ImportKeyStep == 0 {
+		var keyTypes strings.Builder
+		for i, kt := range crypto.KeyTypes {
+			cursor := "  "
+			if i == m.KeyTypeCursor {
+				cursor = "→ "
+			}
+			keyTypes.WriteString(fmt.Sprintf("%s%s\n", cursor, kt.DisplayName()))
+		}
+
+		content := fmt.Sprintf(`
+%s
+
+Select the algorithm of the private key you are importing.
+
+%s
+[↑↓] Navigate  [Enter] Select  [Esc] Cancel
+`, styleTitle.Render("Import Private Key"), keyTypes.String())
+
+		boxedContent := styleBox.Render(content)
+		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+	}
+
 	content := fmt.Sprintf(`
 %s
 
-Set a strong passphrase to encrypt your wallet on disk.%s
+Key type: %s
+
+Paste the private key as raw hex (0x prefix optional) or DER-encoded hex
+as shown in the Hedera portal.
 
 %s
-`, styleTitle.Render("Set Passphrase"), errorMsg, m.Input.View())
+%s
+[Enter] Import  [Esc] Back
+`, styleTitle.Render("Import Private Key"), keyType.DisplayName(), m.Input.View(), errorMsg)
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewDashboard() string {
-	statusLine := ""
-	if m.IsRefreshing {
-		statusLine = "\n🔄 Refreshing account information...\n"
-	} else if m.RefreshError != "" {
-		statusLine = fmt.Sprintf("\n⚠️  Error: %s\n", m.RefreshError)
+func (m Model) viewKeyTypeSelect() string {
+	paths := map[crypto.KeyType]string{
+		crypto.KeyTypeECDSA:   "m/44'/60'/0'/0/0   (MetaMask, HashPack ECDSA)",
+		crypto.KeyTypeED25519: "m/44'/3030'/0'/0'/0'  (HashPack ED25519)",
+	}
+
+	var keyTypes strings.Builder
+	for i, kt := range crypto.KeyTypes {
+		cursor := "  "
+		if i == m.KeyTypeCursor {
+			cursor = "→ "
+		}
+		keyTypes.WriteString(fmt.Sprintf("%s%-18s %s\n", cursor, kt.DisplayName(), paths[kt]))
 	}
 
 	content := fmt.Sprintf(`
 %s
 
-Account: %s
-Balance: %s
-EVM Address: %s%s
-
-[s] Send   [r] Receive   [t] Tokens   [f] Refresh   [h] History   [q] Quit
-`, styleTitle.Render(GetStyledLogo())+"\n"+styleSubTitle.Render("Network: Testnet"), m.AccountID, m.Balance, "0x"+m.EVMAddress, statusLine)
+Choose the key type to derive from your recovery phrase.
 
-	if len(m.TokenBalances) > 0 {
-		content += "\nTokens:\n"
-		for _, token := range m.TokenBalances {
-			alias := ""
-			if m.TokenAliases != nil {
-				if a, ok := m.TokenAliases[token.TokenID]; ok {
-					alias = fmt.Sprintf(" (%s)", a)
-				}
-			}
-			content += fmt.Sprintf("- %s: %d%s\n", token.TokenID, token.Balance, alias)
-		}
-	}
+%s
+[↑↓] Navigate  [Enter] Select
+`, styleTitle.Render("Select Key Type"), keyTypes.String())
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewReceive() string {
-	var content strings.Builder
+func (m Model) viewMnemonicPassphrase() string {
+	prompt := "Enter an optional BIP-39 passphrase (the \"25th word\"), or leave it empty."
+	if m.MnemonicPassStep == 1 {
+		prompt = "Enter the BIP-39 passphrase again to confirm it."
+	}
 
-	content.WriteString(styleTitle.Render("Receive Funds") + "\n\n")
+	content := fmt.Sprintf(`
+%s
 
-	content.WriteString(styleSubTitle.Render("Hedera Account ID") + "\n")
-	content.WriteString(m.AccountID + "\n\n")
+%s
+This is not your wallet password. A different passphrase opens a
+different wallet, and shred cannot recover a forgotten one.
 
-	content.WriteString(styleSubTitle.Render("EVM Address") + "\n")
-	content.WriteString(m.EVMAddress + "\n\n")
+%s
+%s
+[Enter] Continue  [Esc] Back
+`, styleTitle.Render("Recovery Phrase Passphrase"), prompt, m.Input.View(), m.noticeLine())
 
-	qrContent := m.AccountID
-	if m.AccountID == "Unverified" || m.AccountID == "Inactive" {
-		qrContent = m.EVMAddress
-	}
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
 
-	config := qrterminal.Config{
-		Level:     qrterminal.M,
-		Writer:    &content,
-		BlackChar: qrterminal.BLACK,
-		WhiteChar: qrterminal.WHITE,
-		QuietZone: 1,
+func (m Model) viewShareSetup() string {
+	prompt := "How many shares should be needed to recover the phrase?"
+	if m.ShareSetupStep == 1 {
+		prompt = fmt.Sprintf("Any %d shares will recover the phrase. How many shares in total?", m.ShareThreshold)
 	}
-	qrterminal.GenerateWithConfig(qrContent, config)
 
-	content.WriteString("\n\n[c] Copy Account ID  [e] Copy EVM Address  [Esc] Back\n")
+	content := fmt.Sprintf(`
+%s
 
-	boxedContent := styleBox.Render(content.String())
-	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
-}
+%s
 
-func (m Model) viewTokenMenu() string {
-	var content strings.Builder
+%s
+%s
+[Enter] Next  [Esc] Back
+`, styleTitle.Render("Split Recovery Phrase"), prompt, m.Input.View(), m.noticeLine())
 
-	content.WriteString(styleTitle.Render("Manage Tokens") + "\n\n")
-	content.WriteString("Select a token to add an alias:\n\n")
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
 
-	for i, token := range m.TokenBalances {
-		cursor := "  "
-		if i == m.SelectedTokenIndex {
-			cursor = "→ "
+func (m Model) viewShareDisplay() string {
+	var wordsView strings.Builder
+	for i, word := range strings.Fields(m.Shares[m.ShareIndex]) {
+		wordsView.WriteString(fmt.Sprintf("%2d. %-10s ", i+1, word))
+		if (i+1)%5 == 0 {
+			wordsView.WriteString("\n")
 		}
+	}
 
-		alias := ""
-		if m.TokenAliases != nil {
-			if a, ok := m.TokenAliases[token.TokenID]; ok {
-				alias = fmt.Sprintf(" (%s)", a)
-			}
-		}
+	content := fmt.Sprintf(`
+%s
 
-		content.WriteString(fmt.Sprintf("%s%s: %d%s\n", cursor, token.TokenID, token.Balance, alias))
-	}
+Share %d of %d. Any %d shares recover your phrase.
+Write this share down and store it apart from the others.
 
-	content.WriteString("\n" + m.Input.View() + "\n")
-	content.WriteString("\n[↑↓] Navigate  [Enter] Save Alias  [Esc] Back\n")
+%s
+%s
+[Enter] I have written it down
+`, styleTitle.Render("Recovery Share"), m.ShareIndex+1, len(m.Shares), m.ShareThreshold, wordsView.String(), m.noticeLine())
 
-	boxedContent := styleBox.Render(content.String())
+	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewSendSelectToken() string {
-	var content strings.Builder
-	content.WriteString(GetStyledLogo())
-	content.WriteString(styleTitle.Render("\n\nSend Funds - Select Asset") + "\n\n")
-
-	cursor := "  "
-	if m.SelectedTokenIndex == 0 {
-		cursor = "→ "
-	}
-	content.WriteString(fmt.Sprintf("%sHBAR (Balance: %s)\n", cursor, m.Balance))
+func (m Model) viewShareVerify() string {
+	targetIndex := m.ShareVerifyIndex[m.ShareVerifyPos]
+	content := fmt.Sprintf(`
+%s
 
-	for i, token := range m.TokenBalances {
-		cursor = "  "
-		if i+1 == m.SelectedTokenIndex {
-			cursor = "→ "
-		}
+Verify share %d of %d.
+Enter word #%d:
 
-		alias := ""
-		if m.TokenAliases != nil {
-			if a, ok := m.TokenAliases[token.TokenID]; ok {
-				alias = fmt.Sprintf(" (%s)", a)
-			}
-		}
+%s
 
-		content.WriteString(fmt.Sprintf("%s%s: %d%s\n", cursor, token.TokenID, token.Balance, alias))
-	}
+[Esc] Show the share again
+`, styleTitle.Render("Verify Share"), m.ShareIndex+1, len(m.Shares), targetIndex+1, m.Input.View())
 
-	content.WriteString("\n[↑↓] Navigate  [Enter] Select  [Esc] Cancel\n")
-	boxedContent := styleBox.Render(content.String())
+	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewSendRecipient() string {
-	errorMsg := ""
-	if m.SendError != "" {
-		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
-	}
-
-	assetName := "HBAR"
-	if m.SendSelectedToken.TokenID != "" {
-		assetName = m.SendSelectedToken.TokenID
-		if m.TokenAliases != nil {
-			if a, ok := m.TokenAliases[assetName]; ok {
-				assetName = fmt.Sprintf("%s (%s)", assetName, a)
-			}
-		}
+func (m Model) viewShareRecover() string {
+	progress := "Enter one share at a time, with its words separated by spaces."
+	if len(m.RecoveredShares) > 0 {
+		progress = fmt.Sprintf("%d of %d shares entered.", len(m.RecoveredShares), m.RecoveredShares[0].Threshold)
 	}
 
 	content := fmt.Sprintf(`
 %s
 
-Sending: %s
+%s
 
-Enter Recipient (Account ID or EVM Address):
 %s
 %s
-[Enter] Next  [Esc] Back
-`, styleTitle.Render(GetStyledLogo()),  assetName, m.Input.View(), errorMsg)
+[Enter] Add Share  [Esc] Cancel
+`, styleTitle.Render("Recover From Shares"), progress, m.Input.View(), m.noticeLine())
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewSendAmount() string {
-	assetName := "HBAR"
-	maxAmount := m.Balance
-
-	if m.SendSelectedToken.TokenID != "" {
-		assetName = m.SendSelectedToken.TokenID
-		if m.TokenAliases != nil {
-			if a, ok := m.TokenAliases[assetName]; ok {
-				assetName = fmt.Sprintf("%s (%s)", assetName, a)
-			}
-		}
-		maxAmount = fmt.Sprintf("%d", m.SendSelectedToken.Balance)
-	}
-
+func (m Model) viewVerify() string {
+	targetIndex := m.VerifyIndices[m.CurrentVerifyIndex]
 	content := fmt.Sprintf(`
 %s
 
-Sending: %s
-To: %s
-Max Available: %s
+Verify your recovery phrase (%d of %d).
+Enter word #%d:
 
-Enter Amount:
 %s
 
-[Enter] Next  [Esc] Back
-`, styleTitle.Render(GetStyledLogo()), assetName, m.SendRecipient, maxAmount, m.Input.View())
+[Esc] Show the phrase again
+`, styleTitle.Render("Verify Phrase"), m.CurrentVerifyIndex+1, len(m.VerifyIndices), targetIndex+1, m.Input.View())
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewSendConfirm() string {
+func (m Model) viewPassword() string {
 	errorMsg := ""
-	if m.SendError != "" {
-		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	if m.ErrorMessage != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.ErrorMessage)
 	}
 
-	assetName := "HBAR"
-	if m.SendSelectedToken.TokenID != "" {
-		assetName = m.SendSelectedToken.TokenID
-		if m.TokenAliases != nil {
-			if a, ok := m.TokenAliases[assetName]; ok {
-				assetName = fmt.Sprintf("%s (%s)", assetName, a)
-			}
+	content := fmt.Sprintf(`
+%s
+
+Set a strong passphrase to encrypt your wallet on disk.%s
+
+%s
+`, styleTitle.Render("Set Passphrase"), errorMsg, m.Input.View())
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewNetworkSelect() string {
+	var networks strings.Builder
+	for i, name := range hedera_client.Networks {
+		cursor := "  "
+		if i == m.NetworkCursor {
+			cursor = "→ "
 		}
+		network := hedera_client.NetworkConfig{Name: name}
+		networks.WriteString(fmt.Sprintf("%s%s\n", cursor, network.DisplayName()))
 	}
 
 	content := fmt.Sprintf(`
 %s
 
-Please review your transaction:
-
-Asset:     %s
-Amount:    %s
-Recipient: %s
-Memo:      Sent via shred
+Choose the Hedera network this wallet will use.
 
 %s
-[Y/Enter] Confirm & Sign  [Esc] Back
-`, styleTitle.Render(GetStyledLogo()), assetName, m.SendAmount, m.SendRecipient, errorMsg)
+[↑↓] Navigate  [Enter] Select
+`, styleTitle.Render("Select Network"), networks.String())
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewSendSigning() string {
+func (m Model) viewNetworkCustom() string {
+	prompts := []string{
+		"Enter consensus node address (host:port):",
+		"Enter consensus node account ID:",
+		"Enter mirror node REST URL:",
+	}
+
 	errorMsg := ""
-	if m.SendError != "" {
-		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	if m.ErrorMessage != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.ErrorMessage)
 	}
 
 	content := fmt.Sprintf(`
 %s
 
+Configure your local node. Leave blank to use the default.
 
 %s
 
-Enter your wallet passphrase to sign the transaction:
-
 %s
 %s
-[Enter] Sign & Send  [Esc] Cancel
-`, styleTitle.Render(GetStyledLogo()), "Sign Transaction", m.Input.View(), errorMsg)
+[Enter] Next  [Esc] Back
+`, styleTitle.Render("Local Network"), prompts[m.CustomNetworkStep], m.Input.View(), errorMsg)
 
 	boxedContent := styleBox.Render(content)
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
 }
 
-func (m Model) viewHistory() string {
-	var content strings.Builder
-	content.WriteString(GetStyledLogo())
-	content.WriteString(styleTitle.Render("\n\n", "Transaction History") + "\n\n")
+func (m Model) noticeLine() string {
+	if m.ErrorMessage != "" {
+		return fmt.Sprintf("\n⚠️  %s\n", m.ErrorMessage)
+	}
+	if m.StatusMessage != "" {
+		return fmt.Sprintf("\n✅ %s\n", m.StatusMessage)
+	}
+	return ""
+}
 
-	if m.HistoryIsLoading {
-		content.WriteString("Loading transactions...\n")
-	} else if m.HistoryError != "" {
-		content.WriteString(fmt.Sprintf("⚠️  Error: %s\n", m.HistoryError))
+func (m Model) viewChangePassphrase() string {
+	prompts := []string{
+		"Enter your current passphrase:",
+		"Enter a new passphrase:",
+		"Confirm the new passphrase:",
+	}
+
+	errorMsg := ""
+	if m.ErrorMessage != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.ErrorMessage)
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+The wallet is re-encrypted with a fresh salt and nonce.
+
+%s
+
+%s
+%s
+[Enter] Next  [Esc] Cancel
+`, styleTitle.Render("Change Passphrase"), prompts[m.ChangePassStep], m.Input.View(), errorMsg)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) keyTypeLabel() string {
+	label := m.KeyType.DisplayName()
+	if m.Secret != nil && m.Secret.IsLegacyDerivation() {
+		label += " (legacy shred derivation)"
+	}
+	if m.Metadata.MnemonicPassphrase {
+		label += " + BIP-39 passphrase"
+	}
+	return label
+}
+
+func (m Model) accountLabel() string {
+	for _, account := range m.Metadata.Accounts {
+		if account.Index == m.AccountIndex && account.Label != "" {
+			return fmt.Sprintf("#%d %s", account.Index, account.Label)
+		}
+	}
+	return fmt.Sprintf("#%d", m.AccountIndex)
+}
+
+func (m Model) viewAccounts() string {
+	var accounts strings.Builder
+	for i, account := range m.Metadata.Accounts {
+		cursor := "  "
+		if i == m.AccountCursor {
+			cursor = "→ "
+		}
+		active := ""
+		if account.Index == m.AccountIndex {
+			active = " (active)"
+		}
+		accountID := account.AccountID
+		if accountID == "" {
+			accountID = "Unverified"
+		}
+		label := account.Label
+		if label == "" {
+			label = "Account"
+		}
+		accounts.WriteString(fmt.Sprintf("%s#%d %s — %s%s\n", cursor, account.Index, label, accountID, active))
+	}
+
+	footer := "[↑↓] Navigate  [Enter] Switch  [n] New Account  [l] Label  [Esc] Back"
+	if m.EditingAccountLabel {
+		footer = m.Input.View() + "\n\n[Enter] Save Label  [Esc] Cancel"
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+Accounts derived from this wallet's recovery phrase.
+
+%s%s
+%s
+`, styleTitle.Render("Accounts"), accounts.String(), m.noticeLine(), footer)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewDashboard() string {
+	statusLine := ""
+	if m.IsRefreshing {
+		statusLine = "\n🔄 Refreshing account information...\n"
+	} else if m.RefreshError != "" {
+		statusLine = fmt.Sprintf("\n⚠️  Error: %s\n", m.RefreshError)
+	} else {
+		statusLine = m.noticeLine()
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+Account: %s (%s)
+Balance: %s
+EVM Address: %s
+Key Type: %s%s
+
+[s] Send   [r] Receive   [t] Tokens   [n] NFTs   [f] Refresh   [h] History   [a] Accounts   [m] Max Fee   [p] Passphrase   [q] Quit
+`, styleTitle.Render(GetStyledLogo())+"\n"+styleSubTitle.Render("Network: "+m.Network.DisplayName()), m.AccountID, m.accountLabel(), m.Balance+m.fiatLabel("", m.BalanceTinybars, hedera_client.HbarDecimals), "0x"+m.EVMAddress, m.keyTypeLabel(), statusLine)
+
+	if len(m.TokenBalances) > 0 {
+		content += "\nTokens:\n"
+		for _, token := range m.TokenBalances {
+			content += fmt.Sprintf("- %s: %s%s\n", m.tokenName(token), tokenBalance(token), m.tokenFiatLabel(token))
+		}
+	}
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewMaxFee() string {
+	content := fmt.Sprintf(`
+%s
+
+Transactions that could cost more than this are refused, and the network
+rejects any that would be charged more.
+
+Current max fee: %s (default %s)
+
+Max fee in HBAR:
+%s
+%s
+[Enter] Save  [Esc] Back
+`, styleTitle.Render("Max Transaction Fee"), m.Metadata.MaxFee(), hedera_client.DefaultMaxFee, m.Input.View(), m.noticeLine())
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewReceive() string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render("Receive Funds") + "\n\n")
+
+	content.WriteString(styleSubTitle.Render("Hedera Account ID") + "\n")
+	content.WriteString(m.AccountID + "\n\n")
+
+	content.WriteString(styleSubTitle.Render("EVM Address") + "\n")
+	content.WriteString(m.EVMAddress + "\n\n")
+
+	qrContent := m.AccountID
+	if m.AccountID == "Unverified" || m.AccountID == "Inactive" {
+		qrContent = m.EVMAddress
+	}
+
+	config := qrterminal.Config{
+		Level:     qrterminal.M,
+		Writer:    &content,
+		BlackChar: qrterminal.BLACK,
+		WhiteChar: qrterminal.WHITE,
+		QuietZone: 1,
+	}
+	qrterminal.GenerateWithConfig(qrContent, config)
+
+	content.WriteString("\n\n[c] Copy Account ID  [e] Copy EVM Address  [Esc] Back\n")
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewTokenMenu() string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render("Manage Tokens") + "\n\n")
+
+	relationships := m.TokenRelationships
+	switch free := relationships.FreeAutoAssociations(); {
+	case m.TokenMenuLoading && len(relationships.Tokens) == 0:
+	case free < 0:
+		content.WriteString(fmt.Sprintf("Automatic association slots: unlimited (%d used)\n\n", relationships.UsedAutoAssociations))
+	default:
+		content.WriteString(fmt.Sprintf("Automatic association slots: %d of %d free\n\n", free, relationships.MaxAutoAssociations))
+	}
+
+	switch {
+	case m.TokenMenuLoading:
+		content.WriteString("🔄 Loading associated tokens...\n")
+	case len(relationships.Tokens) == 0:
+		content.WriteString("No tokens are associated with this account.\n")
+	}
+
+	for i, token := range relationships.Tokens {
+		cursor := "  "
+		if i == m.SelectedTokenIndex {
+			cursor = "→ "
+		}
+		auto := ""
+		if token.AutomaticAssociation {
+			auto = "  [auto]"
+		}
+		content.WriteString(fmt.Sprintf("%s%s: %s%s\n", cursor, m.tokenName(token.TokenBalance), tokenBalance(token.TokenBalance), auto))
+	}
+
+	content.WriteString(m.noticeLine())
+	if m.EditingTokenAlias {
+		content.WriteString("\n" + m.Input.View() + "\n")
+		content.WriteString("\n[Enter] Save Alias  [Esc] Cancel\n")
+	} else {
+		content.WriteString("\n[↑↓] Navigate  [a] Associate  [d] Dissociate  [l] Alias  [f] Refresh  [Esc] Back\n")
+	}
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewTokenAssociate() string {
+	content := fmt.Sprintf(`
+%s
+
+Associating a token lets this account hold and receive it.
+
+Token ID:
+%s
+%s
+[Enter] Next  [Esc] Back
+`, styleTitle.Render("Associate Token"), m.Input.View(), m.noticeLine())
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) tokenActionTitle() string {
+	if m.TokenAction == "dissociate" {
+		return "Dissociate Token"
+	}
+	return "Associate Token"
+}
+
+// feeLabel shows the estimated fee of the transaction being confirmed. An
+// estimate from the fallback prices is labelled approximate.
+func (m Model) feeLabel() string {
+	switch {
+	case m.FeeEstimated:
+		usd := hedera_client.FormatUSD(m.FeeEstimate.MicroUSD)
+		if m.FeeEstimate.Approximate {
+			usd = "~" + usd + ", approximate: fee schedule unavailable"
+		}
+		fee := m.FeeEstimate.Fee
+		if !strings.EqualFold(m.Config.Currency, "USD") {
+			if fiat := m.fiatLabel("", fee.AsTinybar(), hedera_client.HbarDecimals); fiat != "" {
+				return fmt.Sprintf("≈ %s (%s)%s", fee, usd, fiat)
+			}
+		}
+		return fmt.Sprintf("≈ %s (%s)", fee, usd)
+	case m.FeeError != "":
+		return "unknown (" + m.FeeError + ")"
+	default:
+		return "estimating..."
+	}
+}
+
+// confirmKeys is the footer of a confirm screen; signing without a fee
+// estimate is only offered once the estimate has failed.
+func (m Model) confirmKeys() string {
+	if m.signAnyway() {
+		return "[A] Sign anyway  [Esc] Back"
+	}
+	if !m.FeeEstimated {
+		return "Estimating fee...  [Esc] Back"
+	}
+	return "[Y/Enter] Confirm & Sign  [Esc] Back"
+}
+
+func (m Model) maxFeeLabel() string {
+	if m.HederaClient == nil {
+		return m.Metadata.MaxFee().String()
+	}
+	return m.HederaClient.MaxFee.String()
+}
+
+// sendTotalLabel adds the estimated fee to the amount being sent.
+func (m Model) sendTotalLabel() string {
+	fee := "fee"
+	if m.FeeEstimated {
+		fee = m.FeeEstimate.Fee.String()
+	}
+	if m.SendSelectedToken.TokenID != "" {
+		return fmt.Sprintf("%s + %s", m.sendAmountLabel(), fee)
+	}
+	if !m.FeeEstimated {
+		return fmt.Sprintf("%s ℏ + fee", hedera_client.FormatAmount(m.SendAmount, hedera_client.HbarDecimals))
+	}
+	total := m.SendAmount + m.FeeEstimate.Fee.AsTinybar()
+	return "≈ " + hedera_client.FormatAmount(total, hedera_client.HbarDecimals) + " ℏ" + m.fiatLabel("", total, hedera_client.HbarDecimals)
+}
+
+func (m Model) sendFiatLabel() string {
+	if m.SendNFT != nil {
+		return ""
+	}
+	return m.fiatLabel(m.SendSelectedToken.TokenID, m.SendAmount, m.sendDecimals())
+}
+
+func (m Model) viewTokenConfirm() string {
+	token := hedera_client.TokenBalance{TokenID: m.TokenActionID}
+	for _, related := range m.TokenRelationships.Tokens {
+		if related.TokenID == m.TokenActionID {
+			token = related.TokenBalance
+		}
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+Please review the transaction:
+
+Token:   %s
+Account: %s
+Fee:     %s
+Max Fee: %s
+%s
+%s
+`, styleTitle.Render(m.tokenActionTitle()), m.tokenName(token), m.AccountID, m.feeLabel(), m.maxFeeLabel(), m.noticeLine(), m.confirmKeys())
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewTokenSigning() string {
+	content := fmt.Sprintf(`
+%s
+
+%s %s
+
+%s
+%s
+[Enter] Sign & Submit  [Esc] Back
+`, styleTitle.Render(m.tokenActionTitle()), strings.ToUpper(m.TokenAction[:1])+m.TokenAction[1:], m.TokenActionID, m.Input.View(), m.noticeLine())
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewSendSelectToken() string {
+	var content strings.Builder
+	content.WriteString(GetStyledLogo())
+	content.WriteString(styleTitle.Render("\n\nSend Funds - Select Asset") + "\n\n")
+
+	cursor := "  "
+	if m.SelectedTokenIndex == 0 {
+		cursor = "→ "
+	}
+	content.WriteString(fmt.Sprintf("%sHBAR (Balance: %s)\n", cursor, m.Balance))
+
+	for i, token := range m.TokenBalances {
+		cursor = "  "
+		if i+1 == m.SelectedTokenIndex {
+			cursor = "→ "
+		}
+
+		content.WriteString(fmt.Sprintf("%s%s: %s\n", cursor, m.tokenName(token), tokenBalance(token)))
+	}
+
+	if m.SendTokenLoading {
+		content.WriteString("\n🔄 Looking up the token...\n")
+	}
+	if m.SendError != "" {
+		content.WriteString(fmt.Sprintf("\n⚠️  %s\n", m.SendError))
+	}
+	content.WriteString("\n[↑↓] Navigate  [Enter] Select  [Esc] Cancel\n")
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewSendRecipient() string {
+	errorMsg := ""
+	if m.SendError != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	}
+
+	assetName := m.sendAssetName()
+
+	content := fmt.Sprintf(`
+%s
+
+Sending: %s
+
+Enter Recipient (Account ID or EVM Address):
+%s
+%s
+[Enter] Next  [Esc] Back
+`, styleTitle.Render(GetStyledLogo()),  assetName, m.Input.View(), errorMsg)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewSendAmount() string {
+	assetName := m.sendAssetName()
+	maxAmount := m.Balance
+	if m.SendSelectedToken.TokenID != "" {
+		maxAmount = m.SendSelectedToken.FormatBalance()
+	}
+
+	errorMsg := ""
+	if m.SendError != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+Sending: %s
+To: %s
+Max Available: %s
+
+Enter Amount:
+%s
+%s
+[Enter] Next  [Esc] Back
+`, styleTitle.Render(GetStyledLogo()), assetName, m.SendRecipient, maxAmount, m.Input.View(), errorMsg)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewSendMemo() string {
+	errorMsg := ""
+	if m.SendError != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	} else if m.Config.RequiresMemo(m.SendRecipient) {
+		errorMsg = fmt.Sprintf("\n⚠️  %s requires a memo to credit your deposit.\n", m.SendRecipient)
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+Sending: %s
+To: %s
+
+Enter Memo (%d/%d bytes):
+%s
+%s
+[Enter] Next  [Esc] Back
+`, styleTitle.Render(GetStyledLogo()), m.sendAssetName(), m.SendRecipient, len(m.Input.Value()), hedera_client.MaxMemoBytes, m.Input.View(), errorMsg)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) sendMemoLabel() string {
+	if m.SendMemo == "" {
+		return "(none)"
+	}
+	return m.SendMemo
+}
+
+// sendAmountLabel shows the exact amount that will be sent, with the count
+// of smallest units.
+func (m Model) sendAmountLabel() string {
+	if m.SendNFT != nil {
+		return fmt.Sprintf("1 NFT (serial #%d)", m.SendNFT.SerialNumber)
+	}
+	amount := hedera_client.FormatAmount(m.SendAmount, m.sendDecimals())
+	if m.SendSelectedToken.TokenID == "" {
+		return fmt.Sprintf("%s ℏ (%d tinybars)", amount, m.SendAmount)
+	}
+	if symbol := m.SendSelectedToken.Info.Symbol; symbol != "" {
+		amount += " " + symbol
+	}
+	if m.sendDecimals() == 0 {
+		return amount
+	}
+	return fmt.Sprintf("%s (%d units)", amount, m.SendAmount)
+}
+
+// tokenName is a token's ID followed by its alias, or by its symbol when no
+// alias is set.
+func (m Model) tokenName(token hedera_client.TokenBalance) string {
+	label := m.TokenAliases[token.TokenID]
+	if label == "" && token.Info != nil {
+		label = token.Info.Symbol
+	}
+	if label == "" {
+		return token.TokenID
+	}
+	return fmt.Sprintf("%s (%s)", token.TokenID, label)
+}
+
+func (m Model) sendAssetName() string {
+	if m.SendSelectedToken.TokenID == "" {
+		return "HBAR"
+	}
+	if m.SendNFT != nil {
+		return fmt.Sprintf("%s #%d", m.tokenName(m.SendSelectedToken), m.SendNFT.SerialNumber)
+	}
+	return m.tokenName(m.SendSelectedToken)
+}
+
+// tokenBalance shows a fungible balance with its decimals and an NFT
+// collection as a count of serials.
+func tokenBalance(token hedera_client.TokenBalance) string {
+	if token.Info != nil && token.Info.IsNFT() {
+		return fmt.Sprintf("%d NFTs", token.Balance)
+	}
+	return token.FormatBalance()
+}
+
+// fiatLabel values an amount of HBAR (tokenID "") or of a token at the
+// latest price, or is empty when there is no price.
+func (m Model) fiatLabel(tokenID string, units int64, decimals int) string {
+	price, ok := m.Prices[tokenID]
+	if !ok {
+		return ""
+	}
+	value := hedera_client.FiatValue(units, decimals, price)
+	return fmt.Sprintf(" (≈ %s)", hedera_client.FormatFiat(value, m.Config.Currency))
+}
+
+// historyFiatLabel values a past HBAR amount. Only the latest price is
+// known, so the label says so rather than passing it off as the value then.
+func (m Model) historyFiatLabel(tinybars int64) string {
+	price, ok := m.Prices[""]
+	if !ok {
+		return ""
+	}
+	value := hedera_client.FiatValue(tinybars, hedera_client.HbarDecimals, price)
+	return fmt.Sprintf(" (≈ %s at current price)", hedera_client.FormatFiat(value, m.Config.Currency))
+}
+
+func (m Model) tokenFiatLabel(token hedera_client.TokenBalance) string {
+	if token.Info == nil || token.Info.IsNFT() {
+		return ""
+	}
+	return m.fiatLabel(token.TokenID, int64(token.Balance), token.Info.Decimals)
+}
+
+func (m Model) nftName(nft hedera_client.NFT) string {
+	return fmt.Sprintf("%s #%d", m.tokenName(hedera_client.TokenBalance{TokenID: nft.TokenID, Info: nft.Collection}), nft.SerialNumber)
+}
+
+func (m Model) viewNFTs() string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render("NFTs") + "\n\n")
+
+	switch {
+	case m.NFTsLoading:
+		content.WriteString("🔄 Loading NFTs...\n")
+	case len(m.NFTs) == 0 && m.ErrorMessage == "":
+		content.WriteString("This account holds no NFTs.\n")
+	}
+
+	for i, nft := range m.NFTs {
+		cursor := "  "
+		if i == m.NFTCursor {
+			cursor = "→ "
+		}
+		name := ""
+		if metadata, _ := nft.DecodeMetadata(); metadata != nil && metadata.Name != "" {
+			name = "  " + metadata.Name
+		}
+		content.WriteString(fmt.Sprintf("%s%s%s\n", cursor, m.nftName(nft), name))
+	}
+
+	content.WriteString(m.noticeLine())
+	content.WriteString("\n[↑↓] Navigate  [Enter] Details  [s] Send  [f] Refresh  [Esc] Back\n")
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewNFTDetail() string {
+	nft := m.NFTs[m.NFTCursor]
+
+	var content strings.Builder
+	content.WriteString(styleTitle.Render("NFT Details") + "\n\n")
+
+	collection := nft.TokenID
+	if nft.Collection != nil && nft.Collection.Name != "" {
+		collection = nft.Collection.Name
+	}
+	content.WriteString(fmt.Sprintf("Collection:  %s\n", collection))
+	content.WriteString(fmt.Sprintf("Token ID:    %s\n", nft.TokenID))
+	content.WriteString(fmt.Sprintf("Serial:      %d\n\n", nft.SerialNumber))
+
+	metadata, text := nft.DecodeMetadata()
+	switch {
+	case metadata != nil:
+		fields := []struct{ label, value string }{
+			{"Name", metadata.Name},
+			{"Creator", metadata.Creator},
+			{"Description", metadata.Description},
+			{"Image", metadata.Image},
+			{"Type", metadata.Type},
+		}
+		for _, field := range fields {
+			if field.value != "" {
+				content.WriteString(fmt.Sprintf("%-12s %s\n", field.label+":", field.value))
+			}
+		}
+		if len(metadata.Attributes) > 0 {
+			content.WriteString("\n" + styleSubTitle.Render("Attributes") + "\n")
+			for _, attribute := range metadata.Attributes {
+				content.WriteString(fmt.Sprintf("- %s: %v\n", attribute.TraitType, attribute.Value))
+			}
+		}
+		if len(metadata.Properties) > 0 {
+			content.WriteString("\n" + styleSubTitle.Render("Properties") + "\n")
+			keys := make([]string, 0, len(metadata.Properties))
+			for key := range metadata.Properties {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				content.WriteString(fmt.Sprintf("- %s: %v\n", key, metadata.Properties[key]))
+			}
+		}
+	case text != "":
+		content.WriteString(fmt.Sprintf("Metadata:    %s\n", text))
+	case len(nft.Metadata) > 0:
+		content.WriteString(fmt.Sprintf("Metadata:    0x%x\n", nft.Metadata))
+	default:
+		content.WriteString("Metadata:    (none)\n")
+	}
+
+	content.WriteString(m.noticeLine())
+	content.WriteString("\n[s] Send  [Esc] Back\n")
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewSendConfirm() string {
+	errorMsg := ""
+	if m.SendError != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	}
+	if m.SendMemo == "" && m.Config.RequiresMemo(m.SendRecipient) {
+		errorMsg += fmt.Sprintf("\n⚠️  %s requires a memo; this transfer may not be credited.\n", m.SendRecipient)
+	}
+
+	assetName := m.sendAssetName()
+
+	content := fmt.Sprintf(`
+%s
+
+Please review your transaction:
+
+Asset:     %s
+Amount:    %s
+Recipient: %s
+Memo:      %s
+Fee:       %s
+Max Fee:   %s
+Total:     %s
+
+%s
+%s
+`, styleTitle.Render(GetStyledLogo()), assetName, m.sendAmountLabel()+m.sendFiatLabel(), m.SendRecipient, m.sendMemoLabel(), m.feeLabel(), m.maxFeeLabel(), m.sendTotalLabel(), errorMsg, m.confirmKeys())
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewSendSigning() string {
+	errorMsg := ""
+	if m.SendError != "" {
+		errorMsg = fmt.Sprintf("\n⚠️  %s\n", m.SendError)
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+
+%s
+
+Enter your wallet passphrase to sign the transaction:
+
+%s
+%s
+[Enter] Sign & Send  [Esc] Cancel
+`, styleTitle.Render(GetStyledLogo()), "Sign Transaction", m.Input.View(), errorMsg)
+
+	boxedContent := styleBox.Render(content)
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewHistory() string {
+	var content strings.Builder
+	content.WriteString(GetStyledLogo())
+	content.WriteString(styleTitle.Render("\n\n", "Transaction History") + "\n\n")
+	content.WriteString(fmt.Sprintf("Page %d  ·  %s\n", len(m.HistoryPrevURLs)+1, m.historyFilterSummary()))
+	if m.HistoryQuery.TokenID != "" {
+		content.WriteString("The token filter is checked by the wallet; the mirror node cannot filter by token.\n")
+	}
+	content.WriteString("\n")
+
+	if m.HistoryIsLoading {
+		content.WriteString("Loading transactions...\n")
+	} else if m.HistoryError != "" {
+		content.WriteString(fmt.Sprintf("⚠️  Error: %s\n", m.HistoryError))
 	} else if len(m.HistoryTransactions) == 0 {
 		content.WriteString("No transactions found.\n")
 	} else {
-		for _, tx := range m.HistoryTransactions {
+		for i, tx := range m.HistoryTransactions {
+			cursor := "  "
+			if i == m.HistoryCursor {
+				cursor = "→ "
+			}
+
 			parts := strings.Split(tx.ConsensusTimestamp, ".")
 			tsStr := parts[0]
 
@@ -459,25 +1244,239 @@ func (m Model) viewHistory() string {
 				}
 			}
 
-			amountStr := fmt.Sprintf("%d tℏ", amount)
+			amountStr := hedera_client.FormatAmount(amount, hedera_client.HbarDecimals) + " ℏ"
 			if amount > 0 {
 				amountStr = "+" + amountStr
 			}
+			amountStr += m.historyFiatLabel(amount)
 
 			memo := ""
 			if tx.MemoBase64 != "" {
 				memo = " (Memo)"
 			}
 
-			content.WriteString(fmt.Sprintf("%s  %s  %s%s  (%s)\n", tx.TransactionID, tx.Result, amountStr, memo, tsStr))
+			content.WriteString(fmt.Sprintf("%s%s  %s  %s%s  (%s)\n", cursor, tx.TransactionID, tx.Result, amountStr, memo, tsStr))
 		}
 	}
 
-	content.WriteString("\n")
+	if _, ok := m.Prices[""]; ok && len(m.HistoryTransactions) > 0 {
+		content.WriteString("\nValues are at the current HBAR price, not the price at the time.\n")
+	}
+
+	content.WriteString(m.noticeLine())
+	content.WriteString("\n[↑↓] Navigate  [Enter] Details  ")
+	if len(m.HistoryPrevURLs) > 0 {
+		content.WriteString("[p] Previous Page  ")
+	}
 	if m.HistoryNextURL != "" {
 		content.WriteString("[n] Next Page  ")
 	}
-	content.WriteString("[Esc] Back\n")
+	content.WriteString("[f] Filters  [e] Export  [Esc] Back\n")
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+// historyFilterSummary lists the active history filters.
+func (m Model) historyFilterSummary() string {
+	var filters []string
+	for field := 0; field < historyFilterLimit; field++ {
+		if value := m.historyFilterValue(field); value != "Any" {
+			filters = append(filters, historyFilterLabels[field]+": "+value)
+		}
+	}
+	if len(filters) == 0 {
+		return "All transactions"
+	}
+	return strings.Join(filters, ", ")
+}
+
+var historyFilterLabels = [historyFilterCount]string{
+	historyFilterType:      "Type",
+	historyFilterResult:    "Result",
+	historyFilterDirection: "Direction",
+	historyFilterToken:     "Token",
+	historyFilterSince:     "From",
+	historyFilterUntil:     "To",
+	historyFilterLimit:     "Page size",
+}
+
+func (m Model) historyFilterValue(field int) string {
+	q := m.HistoryQuery
+	value := ""
+	switch field {
+	case historyFilterType:
+		value = q.Type
+	case historyFilterResult:
+		switch q.Result {
+		case hedera_client.ResultSuccess:
+			value = "Success"
+		case hedera_client.ResultFail:
+			value = "Failed"
+		}
+	case historyFilterDirection:
+		switch q.Direction {
+		case hedera_client.DirectionIn:
+			value = "In"
+		case hedera_client.DirectionOut:
+			value = "Out"
+		}
+	case historyFilterLimit:
+		if q.Limit == 0 {
+			return strconv.Itoa(hedera_client.DefaultHistoryLimit)
+		}
+		return strconv.Itoa(q.Limit)
+	default:
+		value = m.historyFilterText(field)
+	}
+	if value == "" {
+		return "Any"
+	}
+	return value
+}
+
+func (m Model) viewHistoryFilter() string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render("Filter History") + "\n\n")
+
+	for field := 0; field < historyFilterCount; field++ {
+		cursor := "  "
+		if field == m.HistoryFilterCursor {
+			cursor = "→ "
+		}
+		value := m.historyFilterValue(field)
+		if field == m.HistoryFilterCursor && m.HistoryFilterEditing {
+			value = m.Input.View()
+		}
+		content.WriteString(fmt.Sprintf("%s%-10s %s\n", cursor, historyFilterLabels[field]+":", value))
+	}
+
+	content.WriteString("\nDates are local days (YYYY-MM-DD) or RFC 3339 times; To includes the\n")
+	content.WriteString("whole day. The mirror node cannot filter by token, so the wallet reads\n")
+	content.WriteString("more of the history to fill each page, which is slower.\n")
+
+	content.WriteString(m.noticeLine())
+	if m.HistoryFilterEditing {
+		content.WriteString("\n[Enter] Save  [Esc] Cancel\n")
+	} else {
+		content.WriteString("\n[↑↓] Navigate  [←→] Change  [Enter] Edit  [x] Clear  [c] Clear All  [Esc] Apply\n")
+	}
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+func (m Model) viewHistoryExport() string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render("Export History") + "\n\n")
+	content.WriteString("Every transaction matching the filters is written, across all pages:\n")
+	content.WriteString(m.historyFilterSummary() + "\n\n")
+
+	for _, format := range hedera_client.ExportFormats {
+		marker := "( )"
+		if format == m.HistoryExportFormat {
+			marker = "(•)"
+		}
+		content.WriteString(fmt.Sprintf("%s %s  ", marker, historyExportFormatNames[format]))
+	}
+	content.WriteString("\n\nFile:\n" + m.Input.View() + "\n")
+
+	content.WriteString(m.noticeLine())
+	if m.HistoryExporting {
+		content.WriteString("\n🔄 Exporting...\n")
+	} else {
+		content.WriteString("\n[Tab] Format  [Enter] Export  [Esc] Back\n")
+	}
+
+	boxedContent := styleBox.Render(content.String())
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+var historyExportFormatNames = map[string]string{
+	hedera_client.ExportCSV:   "CSV",
+	hedera_client.ExportJSONL: "JSON Lines",
+	hedera_client.ExportOFX:   "OFX",
+}
+
+// historyTime renders a mirror node timestamp in local time.
+func historyTime(timestamp string) string {
+	t, err := hedera_client.ParseMirrorTimestamp(timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.Local().Format("2006-01-02 15:04:05.000 MST")
+}
+
+// historyAccount marks the wallet's own account in a transfer list.
+func (m Model) historyAccount(accountID string) string {
+	if accountID == m.AccountID {
+		return accountID + " (you)"
+	}
+	return accountID
+}
+
+// historyTokenTransfer formats a token transfer with the token's decimals
+// and symbol when the wallet holds the token.
+func (m Model) historyTokenTransfer(transfer hedera_client.MirrorTokenTransfer) string {
+	token := hedera_client.TokenBalance{TokenID: transfer.TokenID}
+	for _, held := range m.TokenBalances {
+		if held.TokenID == transfer.TokenID {
+			token = held
+		}
+	}
+	amount := strconv.FormatInt(transfer.Amount, 10)
+	if token.Info != nil {
+		amount = hedera_client.FormatAmount(transfer.Amount, token.Info.Decimals)
+	}
+	return fmt.Sprintf("%s %s", amount, m.tokenName(token))
+}
+
+func (m Model) viewHistoryDetail() string {
+	tx := m.HistoryTransactions[m.HistoryCursor]
+
+	var content strings.Builder
+	content.WriteString(styleTitle.Render("Transaction Details") + "\n\n")
+
+	memo := tx.Memo()
+	if memo == "" {
+		memo = "(none)"
+	}
+	content.WriteString(fmt.Sprintf("ID:          %s\n", tx.TransactionID))
+	content.WriteString(fmt.Sprintf("Type:        %s\n", tx.Name))
+	content.WriteString(fmt.Sprintf("Result:      %s\n", tx.Result))
+	content.WriteString(fmt.Sprintf("Consensus:   %s\n", historyTime(tx.ConsensusTimestamp)))
+	content.WriteString(fmt.Sprintf("Valid Start: %s\n", historyTime(tx.ValidStartTimestamp)))
+	content.WriteString(fmt.Sprintf("Node:        %s\n", tx.Node))
+	content.WriteString(fmt.Sprintf("Fee:         %s ℏ%s\n", hedera_client.FormatAmount(tx.ChargedTxFee, hedera_client.HbarDecimals), m.historyFiatLabel(tx.ChargedTxFee)))
+	content.WriteString(fmt.Sprintf("Memo:        %s\n", memo))
+
+	if len(tx.Transfers) > 0 {
+		content.WriteString("\n" + styleSubTitle.Render("HBAR Transfers") + "\n")
+		for _, transfer := range tx.Transfers {
+			content.WriteString(fmt.Sprintf("  %-22s %s ℏ\n", m.historyAccount(transfer.Account), hedera_client.FormatAmount(transfer.Amount, hedera_client.HbarDecimals)))
+		}
+	}
+	if len(tx.TokenTransfers) > 0 {
+		content.WriteString("\n" + styleSubTitle.Render("Token Transfers") + "\n")
+		for _, transfer := range tx.TokenTransfers {
+			content.WriteString(fmt.Sprintf("  %-22s %s\n", m.historyAccount(transfer.Account), m.historyTokenTransfer(transfer)))
+		}
+	}
+	if len(tx.NFTTransfers) > 0 {
+		content.WriteString("\n" + styleSubTitle.Render("NFT Transfers") + "\n")
+		for _, transfer := range tx.NFTTransfers {
+			content.WriteString(fmt.Sprintf("  %s #%d  %s → %s\n", transfer.TokenID, transfer.SerialNumber, m.historyAccount(transfer.SenderAccountID), m.historyAccount(transfer.ReceiverAccountID)))
+		}
+	}
+
+	if url := m.Network.TransactionURL(tx.ConsensusTimestamp); url != "" {
+		content.WriteString("\n" + url + "\n")
+	}
+
+	content.WriteString(m.noticeLine())
+	content.WriteString("\n[c] Copy Transaction ID  [Esc] Back\n")
 
 	boxedContent := styleBox.Render(content.String())
 	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, boxedContent)
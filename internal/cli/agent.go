@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/divin3circle/shred/internal/agent"
+	"github.com/divin3circle/shred/internal/config"
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+	"golang.org/x/term"
+)
+
+// runAgent unlocks a wallet and serves signing requests until the user
+// stops it. Requests are approved in the terminal unless an auto-approve
+// rule in config.json covers them.
+func (r *runner) runAgent(args []string) error {
+	fs := r.newFlagSet("agent")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	addPassphraseFlags(fs, &flags.passphrase)
+	socket := fs.String("socket", "", "socket path (default: $"+agent.SocketEnv+" or the runtime directory)")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	f, ok := r.stdinFile()
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return fail(ExitUsage, errors.New("the agent needs a terminal to approve signing requests"))
+	}
+
+	path := *socket
+	if path == "" {
+		var err error
+		if path, err = agent.DefaultSocketPath(); err != nil {
+			return err
+		}
+	}
+
+	s, secret, err := r.unlock(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	key, err := secret.PrivateKeyAt(s.index)
+	if err != nil {
+		secret.Wipe()
+		return err
+	}
+	publicKey := key.PublicKey()
+
+	// Requests can still be signed before the account exists; they are just
+	// never auto-approved.
+	accountID, err := s.accountID(&publicKey)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "shred: warning: %v\n", err)
+		accountID = ""
+	}
+
+	server, err := agent.Listen(path)
+	if err != nil {
+		secret.Wipe()
+		return err
+	}
+	defer server.Close()
+	go server.Serve()
+
+	session, err := agent.NewSession(s.wallet.FilePath, secret, s.metadata, s.index, accountID)
+	if err != nil {
+		secret.Wipe()
+		return err
+	}
+	defer session.Close()
+
+	// A broken config file only means no rules are proposed.
+	cfg, _ := config.Load()
+	model := newAgentModel(server, session, cfg.AgentAutoApprove)
+	_, err = tea.NewProgram(model, tea.WithAltScreen(), tea.WithInput(r.stdinSource), tea.WithOutput(r.stdout)).Run()
+	return err
+}
+
+// sendWithAgent builds the transfer, has a running agent sign it and
+// submits it. The wallet file and passphrase are never touched.
+func (r *runner) sendWithAgent(socket, to, amountStr, tokenID, memo string, yes bool) error {
+	path := socket
+	if path == "" {
+		var err error
+		if path, err = agent.DefaultSocketPath(); err != nil {
+			return err
+		}
+	}
+
+	conn, err := agent.Dial(path)
+	if err != nil {
+		return fail(ExitNotFound, err)
+	}
+	defer conn.Close()
+
+	info, err := conn.Info()
+	if err != nil {
+		return fail(ExitAuth, err)
+	}
+	if info.AccountID == "" {
+		return fail(ExitNotFound, errors.New("the agent's account has no Hedera account yet"))
+	}
+
+	client, err := hedera.NewClient(hedera.NetworkConfig{
+		Name:          info.Network,
+		NodeAddress:   info.NodeAddress,
+		NodeAccountID: info.NodeAccountID,
+		MirrorURL:     info.MirrorURL,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if info.MaxFeeTinybars > 0 {
+		client.MaxFee = sdk.HbarFromTinybar(info.MaxFeeTinybars)
+	}
+
+	asset, err := lookupAsset(client, tokenID)
+	if err != nil {
+		return err
+	}
+	amount, err := asset.parse(amountStr)
+	if err != nil {
+		return err
+	}
+	feeNote, err := r.estimateFee(client, tokenID, "", sdk.PrivateKey{})
+	if err != nil {
+		return err
+	}
+
+	if !yes && !r.confirm(fmt.Sprintf("Send %s from %s to %s%s?", asset.label(amount), info.AccountID, to, feeNote)) {
+		return fail(ExitError, errors.New("cancelled"))
+	}
+
+	var tx *sdk.TransferTransaction
+	if tokenID == "" {
+		tx, err = client.NewHbarTransfer(info.AccountID, to, amount, memo, time.Time{})
+	} else {
+		tx, err = client.NewTokenTransfer(info.AccountID, to, tokenID, amount, memo, time.Time{})
+	}
+	if err != nil {
+		return err
+	}
+
+	unsigned, err := tx.ToBytes()
+	if err != nil {
+		return err
+	}
+	signed, err := conn.Sign(unsigned)
+	if err != nil {
+		return fail(ExitAuth, err)
+	}
+	signedTx, err := sdk.TransactionFromBytes(signed)
+	if err != nil {
+		return fmt.Errorf("agent returned an invalid transaction: %w", err)
+	}
+
+	txID, err := client.Submit(signedTx)
+	if err != nil {
+		return fail(ExitNetwork, err)
+	}
+
+	if r.json {
+		return r.printJSON(sendJSON{
+			TransactionID: txID,
+			From:          info.AccountID,
+			To:            to,
+			Amount:        asset.format(amount),
+			TokenID:       tokenID,
+		})
+	}
+	fmt.Fprintf(r.stdout, "Sent %s to %s\nTransaction ID: %s\n", asset.label(amount), to, txID)
+	return nil
+}
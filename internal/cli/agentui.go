@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/divin3circle/shred/internal/agent"
+	"github.com/divin3circle/shred/internal/crypto"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+var (
+	agentBoxStyle = lipgloss.NewStyle().
+			Padding(1, 2)
+
+	agentTitleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500")).
+			Bold(true).
+			Align(lipgloss.Center).
+			Width(80)
+
+	agentSubTitleStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("240")).
+				Bold(true)
+)
+
+const agentLogLines = 8
+
+type agentCallMsg struct {
+	Call *agent.Call
+}
+
+type agentTickMsg time.Time
+
+// agentModel is the agent's terminal screen, where the user approves the
+// requests the session queues. Key presses are the only activity that keeps
+// the session unlocked.
+type agentModel struct {
+	server  *agent.Server
+	session *agent.Session
+
+	// proposedRules are the auto-approve rules in config.json while they
+	// differ from the adopted ones in the wallet metadata.
+	proposedRules []crypto.AgentRule
+	rulesChanged  bool
+
+	log           []string
+	width, height int
+}
+
+func newAgentModel(server *agent.Server, session *agent.Session, configRules []crypto.AgentRule) agentModel {
+	return agentModel{
+		server:        server,
+		session:       session,
+		proposedRules: configRules,
+		rulesChanged:  !crypto.AgentRulesEqual(configRules, session.Metadata.AgentAutoApprove),
+	}
+}
+
+func waitForAgentCall(server *agent.Server) tea.Cmd {
+	return func() tea.Msg {
+		call, ok := <-server.Calls()
+		if !ok {
+			return nil
+		}
+		return agentCallMsg{Call: call}
+	}
+}
+
+func agentTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return agentTickMsg(t)
+	})
+}
+
+func (m agentModel) Init() tea.Cmd {
+	return tea.Batch(waitForAgentCall(m.server), agentTickCmd())
+}
+
+func (m agentModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case agentTickMsg:
+		return m.lockIfIdle(time.Time(msg)), agentTickCmd()
+	case agentCallMsg:
+		// Calls are answered whether or not the session is locked, so a
+		// locked agent refuses requests instead of leaving them waiting.
+		return m.logf("%s", m.session.Handle(msg.Call)), waitForAgentCall(m.server)
+	case tea.KeyMsg:
+		// A key press cannot revive a session that timed out since the
+		// last tick.
+		now := time.Now()
+		m = m.lockIfIdle(now)
+		m.session.Touch(now)
+		return m.handleKey(msg, now)
+	}
+	return m, nil
+}
+
+func (m agentModel) lockIfIdle(now time.Time) agentModel {
+	if m.session.LockIfIdle(now) {
+		return m.logf("locked after %s without a key press", crypto.AutoLockAfter)
+	}
+	return m
+}
+
+func (m agentModel) handleKey(msg tea.KeyMsg, now time.Time) (tea.Model, tea.Cmd) {
+	switch strings.ToLower(msg.String()) {
+	case "y":
+		return m.logf("%s", m.session.Approve(now)), nil
+	case "n":
+		return m.logf("%s", m.session.Deny()), nil
+	case "a":
+		if m.rulesChanged && !m.session.Locked() {
+			if err := m.session.AdoptRules(m.proposedRules); err != nil {
+				return m.logf("failed to save the auto-approve rules: %v", err), nil
+			}
+			m.rulesChanged = false
+			return m.logf("adopted %d auto-approve rule(s)", len(m.proposedRules)), nil
+		}
+	case "r":
+		if m.rulesChanged {
+			m.rulesChanged = false
+			return m.logf("kept the current auto-approve rules"), nil
+		}
+	case "q", "ctrl+c":
+		m.session.Close()
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// logf adds a line to the agent's log. Empty lines are dropped, so session
+// results that have nothing to report can be passed straight through.
+func (m agentModel) logf(format string, args ...any) agentModel {
+	line := fmt.Sprintf(format, args...)
+	if line == "" {
+		return m
+	}
+	m.log = append(m.log, time.Now().Format("15:04:05")+"  "+line)
+	if len(m.log) > agentLogLines {
+		m.log = m.log[len(m.log)-agentLogLines:]
+	}
+	return m
+}
+
+func (m agentModel) View() string {
+	s := m.session
+
+	accountID := s.AccountID
+	if accountID == "" {
+		accountID = "Unverified"
+	}
+	accountLabel := fmt.Sprintf("#%d", s.AccountIndex)
+	for _, account := range s.Metadata.Accounts {
+		if account.Index == s.AccountIndex && account.Label != "" {
+			accountLabel = fmt.Sprintf("#%d %s", account.Index, account.Label)
+		}
+	}
+
+	status := "🔓 Unlocked"
+	if s.Locked() {
+		status = "🔒 Locked (restart the agent to unlock)"
+	}
+
+	var log strings.Builder
+	if len(m.log) == 0 {
+		log.WriteString("No requests yet.\n")
+	}
+	for _, line := range m.log {
+		log.WriteString(line + "\n")
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+Account: %s (%s)
+Network: %s
+Socket: %s
+Status: %s
+Signed: %d   Denied: %d
+%s
+%s
+%s
+`, agentTitleStyle.Render("Signing Agent"), accountID, accountLabel, s.Metadata.NetworkConfig().DisplayName(), m.server.Path(), status, s.Signed, s.Denied, m.viewRules(), log.String(), m.viewPending())
+
+	boxedContent := agentBoxStyle.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, boxedContent)
+}
+
+// viewRules lists the auto-approve rules in use and, when config.json
+// proposes different ones, asks whether to adopt them.
+func (m agentModel) viewRules() string {
+	var b strings.Builder
+	b.WriteString("\nAuto-approve: ")
+	b.WriteString(agentRulesLabel(m.session.Metadata.AgentAutoApprove))
+	b.WriteString("\n")
+	if m.rulesChanged {
+		b.WriteString("\n⚠️  config.json changes the auto-approve rules to: ")
+		b.WriteString(agentRulesLabel(m.proposedRules))
+		b.WriteString("\nOnly adopt them if you made this change.\n[a] Adopt   [r] Keep Current Rules\n")
+	}
+	return b.String()
+}
+
+func agentRulesLabel(rules []crypto.AgentRule) string {
+	if len(rules) == 0 {
+		return "off"
+	}
+	var labels []string
+	for _, rule := range rules {
+		recipients := "any recipient"
+		if len(rule.Recipients) > 0 {
+			recipients = strings.Join(rule.Recipients, ", ")
+		}
+		labels = append(labels, fmt.Sprintf("up to %s to %s", sdk.HbarFromTinybar(rule.MaxTinybars), recipients))
+	}
+	return strings.Join(labels, "; ")
+}
+
+func (m agentModel) viewPending() string {
+	pending := m.session.Pending()
+	if len(pending) == 0 {
+		return "Waiting for signing requests...\n\n[q] Stop Agent"
+	}
+	summary := pending[0].Summary
+
+	var transfers strings.Builder
+	for _, transfer := range summary.HbarTransfers {
+		transfers.WriteString(fmt.Sprintf("  %s  %s\n", transfer.Account, sdk.HbarFromTinybar(transfer.Tinybars).String()))
+	}
+	for _, transfer := range summary.TokenTransfers {
+		transfers.WriteString(fmt.Sprintf("  %s  %d of %s\n", transfer.Account, transfer.Amount, transfer.TokenID))
+	}
+	if summary.NFTTransfers > 0 {
+		transfers.WriteString(fmt.Sprintf("  %d NFT transfer(s)\n", summary.NFTTransfers))
+	}
+
+	memo := summary.Memo
+	if memo == "" {
+		memo = "(none)"
+	}
+
+	return fmt.Sprintf(`%s (%d waiting)
+
+Type: %s
+Transaction ID: %s
+Payer: %s
+Max Fee: %s
+Memo: %s
+%s
+[y] Sign   [n] Deny   [q] Stop Agent`, agentSubTitleStyle.Render("Signing request"), len(pending), summary.Type, summary.TransactionID, summary.Payer, summary.MaxFee, memo, transfers.String())
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// asset is what a transfer moves: HBAR, or a token with the decimals and
+// symbol the mirror node reports for it.
+type asset struct {
+	tokenID  string
+	symbol   string
+	decimals int
+}
+
+func lookupAsset(client *hedera.Client, tokenID string) (asset, error) {
+	if tokenID == "" {
+		return asset{symbol: "ℏ", decimals: hedera.HbarDecimals}, nil
+	}
+	if _, err := sdk.TokenIDFromString(tokenID); err != nil {
+		return asset{}, fail(ExitUsage, fmt.Errorf("invalid token ID: %w", err))
+	}
+
+	info, err := client.GetTokenInfo(tokenID)
+	if err != nil {
+		return asset{}, fail(ExitNetwork, fmt.Errorf("failed to look up token %s: %w", tokenID, err))
+	}
+	if info.IsNFT() {
+		return asset{}, fail(ExitUsage, fmt.Errorf("token %s is an NFT collection; send NFTs from the interactive wallet", tokenID))
+	}
+	symbol := info.Symbol
+	if symbol == "" {
+		symbol = tokenID
+	}
+	return asset{tokenID: tokenID, symbol: symbol, decimals: info.Decimals}, nil
+}
+
+// parse converts --amount to the asset's smallest unit.
+func (a asset) parse(value string) (int64, error) {
+	amount, err := hedera.ParseAmount(value, a.decimals)
+	if err != nil {
+		return 0, fail(ExitUsage, err)
+	}
+	return amount, nil
+}
+
+// format renders an amount in smallest units as an exact decimal.
+func (a asset) format(amount int64) string {
+	return hedera.FormatAmount(amount, a.decimals)
+}
+
+func (a asset) label(amount int64) string {
+	return a.format(amount) + " " + a.symbol
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Exit codes returned by Run, so scripts can tell failures apart.
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitAuth     = 3
+	ExitNetwork  = 4
+	ExitNotFound = 5
+)
+
+const usage = `Usage: shred [command] [flags]
+
+Run without a command to open the interactive wallet.
+
+Commands:
+  wallet list    List wallets
+  balance        Show the HBAR and token balances of an account
+  history        Show recent transactions
+  export         Export transaction history as CSV, JSON Lines or OFX
+  receive        Show the account ID and EVM address to receive funds
+  send           Send HBAR or a token (--agent signs with a running agent)
+  agent          Unlock a wallet and sign requests from other programs
+  tx build       Build an unsigned transfer to sign on another machine
+  tx inspect     Show what a transaction does and how many signatures it has
+  tx sign        Sign a transaction built by "tx build", e.g. offline
+  tx submit      Submit a signed transaction
+
+Common flags:
+  --wallet W     Wallet number (as in "wallet list"), file name, account ID
+                 or EVM address. Optional when there is only one wallet
+  --account N    HD account index (defaults to the wallet's active account)
+  --json         Print machine readable JSON
+
+Passphrase sources for receive, send, agent and tx sign (default: prompt on the terminal):
+  --passphrase-file PATH   First line of a file
+  --passphrase-fd N        A file descriptor, e.g. 0 for stdin or 3
+  --passphrase-env NAME    An environment variable (insecure, opt-in)
+
+Run "shred <command> --help" for the flags of a command.
+`
+
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func fail(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+type runner struct {
+	stdin       *bufio.Reader
+	stdinSource io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+	json        bool
+}
+
+// Run executes a subcommand and returns the process exit code.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	r := &runner{
+		stdin:       bufio.NewReader(stdin),
+		stdinSource: stdin,
+		stdout:      stdout,
+		stderr:      stderr,
+	}
+
+	err := r.dispatch(args)
+	if err == nil {
+		return ExitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	fmt.Fprintf(stderr, "shred: %v\n", err)
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
+
+func (r *runner) dispatch(args []string) error {
+	switch args[0] {
+	case "wallet":
+		if len(args) < 2 || args[1] != "list" {
+			fmt.Fprint(r.stderr, usage)
+			return fail(ExitUsage, errors.New(`unknown wallet command; did you mean "wallet list"?`))
+		}
+		return r.walletList(args[2:])
+	case "balance":
+		return r.balance(args[1:])
+	case "history":
+		return r.history(args[1:])
+	case "export":
+		return r.export(args[1:])
+	case "receive":
+		return r.receive(args[1:])
+	case "send":
+		return r.send(args[1:])
+	case "tx":
+		return r.tx(args[1:])
+	case "agent":
+		return r.runAgent(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(r.stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(r.stderr, usage)
+		return fail(ExitUsage, fmt.Errorf("unknown command %q", args[0]))
+	}
+}
+
+func (r *runner) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(r.stderr)
+	fs.BoolVar(&r.json, "json", false, "print machine readable JSON")
+	return fs
+}
+
+func (r *runner) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fail(ExitUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return fail(ExitUsage, fmt.Errorf("unexpected argument %q", fs.Arg(0)))
+	}
+	return nil
+}
+
+func (r *runner) printJSON(value any) error {
+	encoder := json.NewEncoder(r.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// readLine reads one line from stdin, prompting on stderr so stdout stays
+// clean for --json output.
+func (r *runner) readLine(prompt string) (string, error) {
+	fmt.Fprint(r.stderr, prompt)
+	line, err := r.stdin.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// confirm asks a yes/no question; anything but "y" or "yes" declines.
+func (r *runner) confirm(prompt string) bool {
+	answer, err := r.readLine(prompt + " [y/N] ")
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
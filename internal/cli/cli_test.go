@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	"github.com/divin3circle/shred/internal/hedera"
+)
+
+const (
+	testMnemonic      = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testPassphraseEnv = "SHRED_TEST_PASSPHRASE"
+)
+
+// testHome points the wallet, config and cache directories at a temporary
+// directory.
+func testHome(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv(testPassphraseEnv, "")
+}
+
+// testWallet saves a testnet wallet with signed metadata and returns its
+// path.
+func testWallet(t *testing.T, identifier, passphrase string) string {
+	t.Helper()
+	path, err := crypto.GetWalletPath(identifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := crypto.NewMnemonicSecret([]byte(testMnemonic), nil, crypto.KeyTypeECDSA)
+	defer secret.Wipe()
+	if err := crypto.SaveWallet(secret, []byte(passphrase), path); err != nil {
+		t.Fatal(err)
+	}
+	metadata := crypto.WalletMetadata{CreatedAt: time.Now(), AccountID: "0.0.1001", Network: hedera.NetworkTestnet}
+	if err := crypto.SaveWalletMetadata(path, metadata, secret); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func run(t *testing.T, stdin string, args ...string) (int, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stderr.String()
+}
+
+func TestExitCodesWithoutWallets(t *testing.T) {
+	testHome(t)
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"help"}, ExitOK},
+		{[]string{"balance", "--help"}, ExitOK},
+		{[]string{"frobnicate"}, ExitUsage},
+		{[]string{"wallet"}, ExitUsage},
+		{[]string{"tx"}, ExitUsage},
+		{[]string{"tx", "frobnicate"}, ExitUsage},
+		{[]string{"balance", "--no-such-flag"}, ExitUsage},
+		{[]string{"balance", "extra"}, ExitUsage},
+		{[]string{"wallet", "list"}, ExitOK},
+		{[]string{"balance"}, ExitNotFound},
+		{[]string{"send", "--amount", "1"}, ExitUsage},
+		{[]string{"send", "--to", "nobody", "--amount", "1"}, ExitUsage},
+		{[]string{"send", "--agent", "--socket", filepath.Join(t.TempDir(), "none.sock"), "--to", "0.0.2002", "--amount", "1"}, ExitNotFound},
+		{[]string{"tx", "inspect", "--in", filepath.Join(t.TempDir(), "missing.tx")}, ExitNotFound},
+		{[]string{"tx", "inspect"}, ExitUsage},
+		// The agent approves requests on a terminal, which a test lacks.
+		{[]string{"agent"}, ExitUsage},
+	}
+	for _, tc := range tests {
+		if code, stderr := run(t, "not a transaction", tc.args...); code != tc.want {
+			t.Errorf("shred %s: exit %d, want %d (%s)", strings.Join(tc.args, " "), code, tc.want, strings.TrimSpace(stderr))
+		}
+	}
+}
+
+func TestExitCodesWithWallets(t *testing.T) {
+	testHome(t)
+	path := testWallet(t, "first", "right passphrase")
+	receive := []string{"receive", "--wallet", "1", "--passphrase-env", testPassphraseEnv}
+
+	t.Setenv(testPassphraseEnv, "wrong passphrase")
+	if code, stderr := run(t, "", receive...); code != ExitAuth {
+		t.Errorf("wrong passphrase: exit %d, want %d (%s)", code, ExitAuth, stderr)
+	}
+	os.Unsetenv(testPassphraseEnv)
+	if code, stderr := run(t, "", receive...); code != ExitAuth {
+		t.Errorf("unset passphrase variable: exit %d, want %d (%s)", code, ExitAuth, stderr)
+	}
+	if code, stderr := run(t, "", "receive", "--wallet", "1", "--passphrase-env", testPassphraseEnv, "--passphrase-fd", "0"); code != ExitUsage {
+		t.Errorf("two passphrase sources: exit %d, want %d (%s)", code, ExitUsage, stderr)
+	}
+
+	// Metadata edited outside the wallet fails its check once unlocked.
+	metaPath := crypto.GetMetadataPath(path)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(metaPath, bytes.Replace(data, []byte("0.0.1001"), []byte("0.0.6666"), 1), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(testPassphraseEnv, "right passphrase")
+	if code, stderr := run(t, "", receive...); code != ExitAuth {
+		t.Errorf("tampered metadata: exit %d, want %d (%s)", code, ExitAuth, stderr)
+	}
+
+	testWallet(t, "second", "right passphrase")
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"balance"}, ExitUsage},
+		{[]string{"balance", "--wallet", "9"}, ExitNotFound},
+		{[]string{"history", "--wallet", "2", "--result", "maybe"}, ExitUsage},
+		{[]string{"history", "--wallet", "2", "--direction", "sideways"}, ExitUsage},
+		{[]string{"history", "--wallet", "2", "--limit", "0"}, ExitUsage},
+		{[]string{"export", "--wallet", "2", "--format", "xls"}, ExitUsage},
+	}
+	for _, tc := range tests {
+		if code, stderr := run(t, "", tc.args...); code != tc.want {
+			t.Errorf("shred %s: exit %d, want %d (%s)", strings.Join(tc.args, " "), code, tc.want, strings.TrimSpace(stderr))
+		}
+	}
+}
@@ -0,0 +1,483 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/divin3circle/shred/internal/config"
+	"github.com/divin3circle/shred/internal/crypto"
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+type walletJSON struct {
+	Number     int       `json:"number"`
+	File       string    `json:"file"`
+	Path       string    `json:"path"`
+	Network    string    `json:"network"`
+	AccountID  string    `json:"account_id,omitempty"`
+	EVMAddress string    `json:"evm_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r *runner) walletList(args []string) error {
+	fs := r.newFlagSet("wallet list")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	wallets, err := crypto.ListWallets()
+	if err != nil {
+		return err
+	}
+
+	if r.json {
+		list := make([]walletJSON, 0, len(wallets))
+		for i, wallet := range wallets {
+			list = append(list, walletJSON{
+				Number:     i + 1,
+				File:       wallet.FileName,
+				Path:       wallet.FilePath,
+				Network:    wallet.Network,
+				AccountID:  wallet.AccountID,
+				EVMAddress: wallet.EVMAddress,
+				CreatedAt:  wallet.CreatedAt,
+			})
+		}
+		return r.printJSON(list)
+	}
+
+	if len(wallets) == 0 {
+		fmt.Fprintln(r.stdout, "No wallets found.")
+		return nil
+	}
+	for i, wallet := range wallets {
+		accountID := wallet.AccountID
+		if accountID == "" {
+			accountID = "-"
+		}
+		fmt.Fprintf(r.stdout, "%d  %-10s  %-12s  %s  %s\n", i+1, wallet.Network, accountID, wallet.EVMAddress, wallet.FileName)
+	}
+	return nil
+}
+
+type balanceJSON struct {
+	AccountID string             `json:"account_id"`
+	Network   string             `json:"network"`
+	Hbar      string             `json:"hbar"`
+	Tinybars  int64              `json:"tinybars"`
+	Tokens    []tokenBalanceJSON `json:"tokens"`
+}
+
+// tokenBalanceJSON keeps the raw balance in the token's smallest unit; the
+// other fields are empty when the token could not be looked up.
+type tokenBalanceJSON struct {
+	TokenID  string `json:"token_id"`
+	Balance  uint64 `json:"balance"`
+	Amount   string `json:"amount,omitempty"`
+	Decimals *int   `json:"decimals,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+func (r *runner) balance(args []string) error {
+	fs := r.newFlagSet("balance")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	s, err := r.openReadOnly(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	accountID, err := s.accountID(nil)
+	if err != nil {
+		return err
+	}
+	id, err := sdk.AccountIDFromString(accountID)
+	if err != nil {
+		return fmt.Errorf("invalid account ID: %w", err)
+	}
+
+	info, err := s.client.GetAccountBalance(id)
+	if err != nil {
+		return fail(ExitNetwork, fmt.Errorf("failed to fetch balance: %w", err))
+	}
+
+	if r.json {
+		tokens := make([]tokenBalanceJSON, 0, len(info.Tokens))
+		for _, token := range info.Tokens {
+			entry := tokenBalanceJSON{TokenID: token.TokenID, Balance: token.Balance}
+			if token.Info != nil {
+				entry.Amount = token.FormatBalance()
+				entry.Decimals = &token.Info.Decimals
+				entry.Symbol = token.Info.Symbol
+				entry.Name = token.Info.Name
+			}
+			tokens = append(tokens, entry)
+		}
+		return r.printJSON(balanceJSON{
+			AccountID: accountID,
+			Network:   s.client.Network.Name,
+			Hbar:      info.Balance.String(),
+			Tinybars:  info.Balance.AsTinybar(),
+			Tokens:    tokens,
+		})
+	}
+
+	fmt.Fprintf(r.stdout, "Account: %s (%s)\n", accountID, s.client.Network.DisplayName())
+	fmt.Fprintf(r.stdout, "Balance: %s\n", info.Balance.String())
+	for _, token := range info.Tokens {
+		alias := s.metadata.TokenAliases[token.TokenID]
+		if alias == "" && token.Info != nil {
+			alias = token.Info.Symbol
+		}
+		if alias != "" {
+			alias = " (" + alias + ")"
+		}
+		fmt.Fprintf(r.stdout, "  %s%s: %s\n", token.TokenID, alias, token.FormatBalance())
+	}
+	return nil
+}
+
+// historyFlags are the filters shared by history and export.
+type historyFlags struct {
+	txType    string
+	result    string
+	direction string
+	tokenID   string
+	since     string
+	until     string
+}
+
+func addHistoryFlags(fs *flag.FlagSet, flags *historyFlags) {
+	fs.StringVar(&flags.txType, "type", "", "transaction type, e.g. CRYPTOTRANSFER")
+	fs.StringVar(&flags.result, "result", "", "success or fail")
+	fs.StringVar(&flags.direction, "direction", "", "in or out")
+	fs.StringVar(&flags.tokenID, "token", "", "only transactions that move this token")
+	fs.StringVar(&flags.since, "since", "", "start date (YYYY-MM-DD) or RFC 3339 time")
+	fs.StringVar(&flags.until, "until", "", "end date, inclusive, or RFC 3339 time, exclusive")
+}
+
+// query turns the flags into a history query without an account.
+func (f historyFlags) query() (hedera.HistoryQuery, error) {
+	query := hedera.HistoryQuery{
+		Type:    strings.ToUpper(f.txType),
+		TokenID: f.tokenID,
+	}
+	switch f.result {
+	case "", hedera.ResultSuccess, hedera.ResultFail:
+		query.Result = f.result
+	default:
+		return query, fail(ExitUsage, fmt.Errorf("--result must be %s or %s", hedera.ResultSuccess, hedera.ResultFail))
+	}
+	switch f.direction {
+	case "":
+	case "in":
+		query.Direction = hedera.DirectionIn
+	case "out":
+		query.Direction = hedera.DirectionOut
+	default:
+		return query, fail(ExitUsage, errors.New("--direction must be in or out"))
+	}
+	if query.TokenID != "" {
+		if _, err := sdk.TokenIDFromString(query.TokenID); err != nil {
+			return query, fail(ExitUsage, fmt.Errorf("invalid token ID: %w", err))
+		}
+	}
+	var err error
+	if query.Since, query.Until, err = hedera.ParseHistoryRange(f.since, f.until); err != nil {
+		return query, fail(ExitUsage, err)
+	}
+	return query, nil
+}
+
+func (r *runner) history(args []string) error {
+	fs := r.newFlagSet("history")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	var filters historyFlags
+	addHistoryFlags(fs, &filters)
+	limit := fs.Int("limit", hedera.DefaultHistoryLimit, "maximum number of transactions")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+	if *limit <= 0 {
+		return fail(ExitUsage, errors.New("--limit must be positive"))
+	}
+	query, err := filters.query()
+	if err != nil {
+		return err
+	}
+	query.Limit = *limit
+
+	s, err := r.openReadOnly(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	accountID, err := s.accountID(nil)
+	if err != nil {
+		return err
+	}
+
+	query.AccountID = accountID
+	var transactions []hedera.MirrorTransaction
+	next := ""
+	for len(transactions) < *limit {
+		page, err := s.client.GetTransactions(query, next)
+		if err != nil {
+			return fail(ExitNetwork, fmt.Errorf("failed to fetch history: %w", err))
+		}
+		transactions = append(transactions, page.Transactions...)
+		if page.Next == "" {
+			break
+		}
+		next = page.Next
+	}
+	if len(transactions) > *limit {
+		transactions = transactions[:*limit]
+	}
+
+	if r.json {
+		if transactions == nil {
+			transactions = []hedera.MirrorTransaction{}
+		}
+		return r.printJSON(transactions)
+	}
+
+	if len(transactions) == 0 {
+		fmt.Fprintln(r.stdout, "No transactions found.")
+		return nil
+	}
+	for _, tx := range transactions {
+		var amount int64
+		for _, transfer := range tx.Transfers {
+			if transfer.Account == accountID {
+				amount += transfer.Amount
+			}
+		}
+		timestamp := tx.ConsensusTimestamp
+		if t, err := hedera.ParseMirrorTimestamp(tx.ConsensusTimestamp); err == nil {
+			timestamp = t.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(r.stdout, "%s  %-40s  %-22s  %-8s  %s\n", timestamp, tx.TransactionID, tx.Name, tx.Result, sdk.HbarFromTinybar(amount).String())
+	}
+	return nil
+}
+
+type receiveJSON struct {
+	AccountID  string `json:"account_id"`
+	EVMAddress string `json:"evm_address,omitempty"`
+	PublicKey  string `json:"public_key"`
+	Network    string `json:"network"`
+}
+
+func (r *runner) receive(args []string) error {
+	fs := r.newFlagSet("receive")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	addPassphraseFlags(fs, &flags.passphrase)
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	// The address is derived from the key instead of read from metadata, so
+	// a tampered file cannot redirect incoming funds.
+	s, secret, err := r.unlock(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	keyType := secret.KeyType
+	key, err := secret.PrivateKeyAt(s.index)
+	secret.Wipe()
+	if err != nil {
+		return err
+	}
+	publicKey := key.PublicKey()
+	evmAddress := crypto.CalculateEVMAddress(key, keyType)
+
+	// The key's addresses are still worth printing when the account cannot
+	// be looked up.
+	accountID, err := s.accountID(&publicKey)
+	if err != nil {
+		var exitErr *exitError
+		if !errors.As(err, &exitErr) || (exitErr.code != ExitNotFound && exitErr.code != ExitNetwork) {
+			return err
+		}
+		if exitErr.code == ExitNetwork {
+			fmt.Fprintf(r.stderr, "shred: warning: %v\n", err)
+		}
+		accountID = ""
+	}
+
+	if r.json {
+		return r.printJSON(receiveJSON{
+			AccountID:  accountID,
+			EVMAddress: evmAddress,
+			PublicKey:  publicKey.StringRaw(),
+			Network:    s.client.Network.Name,
+		})
+	}
+
+	if accountID == "" {
+		accountID = "unknown"
+	}
+	fmt.Fprintf(r.stdout, "Network:     %s\n", s.client.Network.DisplayName())
+	fmt.Fprintf(r.stdout, "Account ID:  %s\n", accountID)
+	if evmAddress != "" {
+		fmt.Fprintf(r.stdout, "EVM Address: 0x%s\n", strings.TrimPrefix(evmAddress, "0x"))
+	}
+	fmt.Fprintf(r.stdout, "Public Key:  %s\n", publicKey.StringRaw())
+	return nil
+}
+
+// checkMemo rejects memos the network would refuse and warns when a
+// recipient listed in memo_required_accounts gets none.
+func (r *runner) checkMemo(to, memo string) error {
+	if err := hedera.ValidateMemo(memo); err != nil {
+		return fail(ExitUsage, err)
+	}
+	// A broken config file only costs the warning.
+	cfg, _ := config.Load()
+	if memo == "" && cfg.RequiresMemo(to) {
+		fmt.Fprintf(r.stderr, "shred: warning: %s requires a memo; without --memo the transfer may not be credited\n", to)
+	}
+	return nil
+}
+
+// estimateFee describes the expected fee of a transfer for the confirmation
+// prompt, and refuses transfers that would cost more than the wallet's max
+// fee. With a payer, the network's fee schedule is read when none is cached;
+// otherwise the estimate may use the approximate fallback prices. Without an
+// exchange rate there is no estimate; the network still enforces the cap.
+func (r *runner) estimateFee(client *hedera.Client, tokenID, payerID string, key sdk.PrivateKey) (string, error) {
+	if payerID != "" {
+		client.LoadFeeSchedule(payerID, key)
+	}
+	estimate, err := client.EstimateFee(hedera.TransferFeeKind(tokenID, false))
+	if err != nil {
+		fmt.Fprintf(r.stderr, "shred: warning: could not estimate the fee: %v\n", err)
+		return "", nil
+	}
+	if err := client.CheckFee(estimate.Fee); err != nil {
+		return "", fail(ExitError, err)
+	}
+	if estimate.Approximate {
+		return fmt.Sprintf(" (fee ≈ %s, approximate: fee schedule unavailable)", estimate.Fee), nil
+	}
+	return fmt.Sprintf(" (fee ≈ %s)", estimate.Fee), nil
+}
+
+type sendJSON struct {
+	TransactionID string `json:"transaction_id"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Amount        string `json:"amount"`
+	TokenID       string `json:"token_id,omitempty"`
+}
+
+func (r *runner) send(args []string) error {
+	fs := r.newFlagSet("send")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	addPassphraseFlags(fs, &flags.passphrase)
+	to := fs.String("to", "", "recipient account ID")
+	amountStr := fs.String("amount", "", "amount to send")
+	tokenID := fs.String("token", "", "token ID (default: HBAR)")
+	memo := fs.String("memo", "", "transaction memo, e.g. an exchange deposit tag")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	useAgent := fs.Bool("agent", false, "sign with a running agent instead of unlocking the wallet")
+	socket := fs.String("socket", "", "agent socket path (with --agent)")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	if *to == "" || *amountStr == "" {
+		return fail(ExitUsage, errors.New("--to and --amount are required"))
+	}
+	if _, err := sdk.AccountIDFromString(*to); err != nil {
+		return fail(ExitUsage, fmt.Errorf("invalid recipient: %w", err))
+	}
+	if err := r.checkMemo(*to, *memo); err != nil {
+		return err
+	}
+
+	if *useAgent {
+		return r.sendWithAgent(*socket, *to, *amountStr, *tokenID, *memo, *yes)
+	}
+
+	// The amount is checked against the asset's decimals before asking for
+	// the passphrase.
+	ro, err := r.openReadOnly(flags)
+	if err != nil {
+		return err
+	}
+	asset, err := lookupAsset(ro.client, *tokenID)
+	ro.client.Close()
+	if err != nil {
+		return err
+	}
+	amount, err := asset.parse(*amountStr)
+	if err != nil {
+		return err
+	}
+
+	s, secret, err := r.unlock(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	key, err := secret.PrivateKeyAt(s.index)
+	secret.Wipe()
+	if err != nil {
+		return err
+	}
+	publicKey := key.PublicKey()
+
+	senderID, err := s.accountID(&publicKey)
+	if err != nil {
+		return err
+	}
+	feeNote, err := r.estimateFee(s.client, *tokenID, senderID, key)
+	if err != nil {
+		return err
+	}
+
+	if !*yes && !r.confirm(fmt.Sprintf("Send %s from %s to %s%s?", asset.label(amount), senderID, *to, feeNote)) {
+		return fail(ExitError, errors.New("cancelled"))
+	}
+
+	var txID string
+	if *tokenID == "" {
+		txID, err = s.client.TransferHbar(senderID, *to, amount, *memo, key)
+	} else {
+		txID, err = s.client.TransferToken(senderID, *to, *tokenID, amount, *memo, key)
+	}
+	if err != nil {
+		return fail(ExitNetwork, err)
+	}
+
+	if r.json {
+		return r.printJSON(sendJSON{
+			TransactionID: txID,
+			From:          senderID,
+			To:            *to,
+			Amount:        asset.format(amount),
+			TokenID:       *tokenID,
+		})
+	}
+	fmt.Fprintf(r.stdout, "Sent %s to %s\nTransaction ID: %s\n", asset.label(amount), *to, txID)
+	return nil
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/divin3circle/shred/internal/hedera"
+)
+
+type exportJSON struct {
+	File    string `json:"file"`
+	Format  string `json:"format"`
+	Entries int    `json:"entries"`
+}
+
+// export writes every transaction matching the history filters as a ledger,
+// for bookkeeping. It prints the ledger unless --out names a file.
+func (r *runner) export(args []string) error {
+	fs := r.newFlagSet("export")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	var filters historyFlags
+	addHistoryFlags(fs, &filters)
+	format := fs.String("format", hedera.ExportCSV, "csv, jsonl or ofx")
+	out := fs.String("out", "", "write the ledger to a file instead of printing it")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+	*format = strings.ToLower(*format)
+	if !slices.Contains(hedera.ExportFormats, *format) {
+		return fail(ExitUsage, fmt.Errorf("--format must be one of %s", strings.Join(hedera.ExportFormats, ", ")))
+	}
+	query, err := filters.query()
+	if err != nil {
+		return err
+	}
+
+	s, err := r.openReadOnly(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	if query.AccountID, err = s.accountID(nil); err != nil {
+		return err
+	}
+
+	entries, err := s.client.LedgerEntries(query)
+	if err != nil {
+		return fail(ExitNetwork, fmt.Errorf("failed to fetch history: %w", err))
+	}
+
+	var ledger bytes.Buffer
+	if err := hedera.WriteLedger(&ledger, *format, query.AccountID, entries, query.Since, query.Until); err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err := r.stdout.Write(ledger.Bytes())
+		return err
+	}
+	if err := os.WriteFile(*out, ledger.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+
+	if r.json {
+		return r.printJSON(exportJSON{File: *out, Format: *format, Entries: len(entries)})
+	}
+	fmt.Fprintf(r.stdout, "Wrote %d entries to %s\n", len(entries), *out)
+	return nil
+}
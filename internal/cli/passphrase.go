@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const maxPassphraseLen = 4096
+
+type passphraseFlags struct {
+	file string
+	fd   int
+	env  string
+}
+
+func addPassphraseFlags(fs *flag.FlagSet, flags *passphraseFlags) {
+	fs.StringVar(&flags.file, "passphrase-file", "", "read the passphrase from the first line of a file")
+	fs.IntVar(&flags.fd, "passphrase-fd", -1, "read the passphrase from a file descriptor (0 for stdin)")
+	fs.StringVar(&flags.env, "passphrase-env", "", "read the passphrase from the named environment variable (insecure)")
+}
+
+// readPassphrase returns the wallet passphrase from the source selected by
+// flags, or prompts on the terminal without echo. The caller must wipe the
+// returned buffer.
+func (r *runner) readPassphrase(flags passphraseFlags) ([]byte, error) {
+	sources := 0
+	for _, set := range []bool{flags.file != "", flags.fd >= 0, flags.env != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, fail(ExitUsage, errors.New("use only one of --passphrase-file, --passphrase-fd and --passphrase-env"))
+	}
+
+	switch {
+	case flags.file != "":
+		info, err := os.Stat(flags.file)
+		if err != nil {
+			return nil, fail(ExitAuth, fmt.Errorf("failed to read passphrase file: %w", err))
+		}
+		if info.Mode().Perm()&0o077 != 0 {
+			fmt.Fprintf(r.stderr, "shred: warning: %s is readable by other users\n", flags.file)
+		}
+		f, err := os.Open(flags.file)
+		if err != nil {
+			return nil, fail(ExitAuth, fmt.Errorf("failed to read passphrase file: %w", err))
+		}
+		defer f.Close()
+		return readPassphraseFrom(f)
+	case flags.fd == 0:
+		// Stdin is shared with the confirmation prompt, so only the first
+		// line is consumed.
+		line, err := r.stdin.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			wipe(line)
+			return nil, fail(ExitAuth, fmt.Errorf("failed to read passphrase: %w", err))
+		}
+		return trimPassphrase(line), nil
+	case flags.fd > 0:
+		f := os.NewFile(uintptr(flags.fd), "passphrase-fd")
+		if f == nil {
+			return nil, fail(ExitUsage, fmt.Errorf("invalid file descriptor %d", flags.fd))
+		}
+		defer f.Close()
+		return readPassphraseFrom(f)
+	case flags.env != "":
+		value, ok := os.LookupEnv(flags.env)
+		if !ok {
+			return nil, fail(ExitAuth, fmt.Errorf("environment variable %s is not set", flags.env))
+		}
+		fmt.Fprintf(r.stderr, "shred: warning: reading the passphrase from $%s; environment variables can leak to other processes and logs\n", flags.env)
+		os.Unsetenv(flags.env)
+		return []byte(value), nil
+	}
+
+	f, ok := r.stdinFile()
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, fail(ExitAuth, errors.New("no terminal for the passphrase prompt; use --passphrase-file, --passphrase-fd or --passphrase-env"))
+	}
+	fmt.Fprint(r.stderr, "Passphrase: ")
+	passphrase, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(r.stderr)
+	if err != nil {
+		return nil, fail(ExitAuth, fmt.Errorf("failed to read passphrase: %w", err))
+	}
+	return passphrase, nil
+}
+
+func (r *runner) stdinFile() (*os.File, bool) {
+	f, ok := r.stdinSource.(*os.File)
+	return f, ok
+}
+
+func readPassphraseFrom(reader io.Reader) ([]byte, error) {
+	buf := make([]byte, maxPassphraseLen+1)
+	n, err := io.ReadFull(reader, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		wipe(buf)
+		return nil, fail(ExitAuth, fmt.Errorf("failed to read passphrase: %w", err))
+	}
+	if n > maxPassphraseLen {
+		wipe(buf)
+		return nil, fail(ExitAuth, errors.New("passphrase is too long"))
+	}
+
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i+1]
+	}
+	passphrase := trimPassphrase(append([]byte(nil), line...))
+	wipe(buf)
+	return passphrase, nil
+}
+
+// trimPassphrase drops the line ending in place.
+func trimPassphrase(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r"))
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
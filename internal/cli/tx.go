@@ -0,0 +1,423 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/divin3circle/shred/internal/agent"
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+	"github.com/mdp/qrterminal/v3"
+)
+
+// maxQRBytes is what a QR code holds at the lowest error correction level.
+const maxQRBytes = 2953
+
+// defaultValidStartDelay is how far ahead "tx build" starts a transaction
+// without --valid-start. The network only accepts a transaction for three
+// minutes from its start, which is too little to carry it to an offline
+// machine and back.
+const defaultValidStartDelay = 10 * time.Minute
+
+// Offline signing moves frozen transactions between machines in three
+// steps: "tx build" on an online machine, "tx sign" on the offline one and
+// "tx submit" back online. Transactions are written as raw bytes to files
+// and as base64 everywhere else; either form is accepted as input.
+func (r *runner) tx(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(r.stderr, usage)
+		return fail(ExitUsage, errors.New("missing tx command; use build, inspect, sign or submit"))
+	}
+
+	switch args[0] {
+	case "build":
+		return r.txBuild(args[1:])
+	case "inspect":
+		return r.txInspect(args[1:])
+	case "sign":
+		return r.txSign(args[1:])
+	case "submit":
+		return r.txSubmit(args[1:])
+	default:
+		fmt.Fprint(r.stderr, usage)
+		return fail(ExitUsage, fmt.Errorf("unknown tx command %q", args[0]))
+	}
+}
+
+type txJSON struct {
+	TransactionID string `json:"transaction_id"`
+	Transaction   string `json:"transaction"`
+}
+
+type txOutputFlags struct {
+	out string
+	qr  bool
+}
+
+func addTxOutputFlags(fs *flag.FlagSet, flags *txOutputFlags) {
+	fs.StringVar(&flags.out, "out", "", "write the transaction bytes to a file instead of printing base64")
+	fs.BoolVar(&flags.qr, "qr", false, "also print the transaction as a QR code")
+}
+
+func (r *runner) txBuild(args []string) error {
+	fs := r.newFlagSet("tx build")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	from := fs.String("from", "", "payer account ID (default: the wallet's account)")
+	network := fs.String("network", "", "network to build for (default: the wallet's network)")
+	to := fs.String("to", "", "recipient account ID")
+	amountStr := fs.String("amount", "", "amount to send")
+	tokenID := fs.String("token", "", "token ID (default: HBAR)")
+	memo := fs.String("memo", "", "transaction memo, e.g. an exchange deposit tag")
+	validStartStr := fs.String("valid-start", "", "when the transaction becomes valid: a delay like 30m or an RFC 3339 time (default: 10m)")
+	var output txOutputFlags
+	addTxOutputFlags(fs, &output)
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	if *to == "" || *amountStr == "" {
+		return fail(ExitUsage, errors.New("--to and --amount are required"))
+	}
+	if _, err := sdk.AccountIDFromString(*to); err != nil {
+		return fail(ExitUsage, fmt.Errorf("invalid recipient: %w", err))
+	}
+	if err := r.checkMemo(*to, *memo); err != nil {
+		return err
+	}
+	validStart, err := parseValidStart(*validStartStr)
+	if err != nil {
+		return err
+	}
+
+	client, senderID, err := r.txClient(flags, *network, *from)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	asset, err := lookupAsset(client, *tokenID)
+	if err != nil {
+		return err
+	}
+	amount, err := asset.parse(*amountStr)
+	if err != nil {
+		return err
+	}
+
+	var tx *sdk.TransferTransaction
+	if *tokenID == "" {
+		tx, err = client.NewHbarTransfer(senderID, *to, amount, *memo, validStart)
+	} else {
+		tx, err = client.NewTokenTransfer(senderID, *to, *tokenID, amount, *memo, validStart)
+	}
+	if err != nil {
+		return fail(ExitUsage, err)
+	}
+
+	data, err := tx.ToBytes()
+	if err != nil {
+		return err
+	}
+	if !r.json {
+		fmt.Fprintf(r.stderr, "Valid from %s until %s\n", validStart.UTC().Format(time.RFC3339), validStart.Add(hedera.MaxValidDuration).UTC().Format(time.RFC3339))
+	}
+	return r.writeTransaction(tx, data, output)
+}
+
+func (r *runner) txInspect(args []string) error {
+	fs := r.newFlagSet("tx inspect")
+	in := fs.String("in", "-", "transaction file, or - for stdin")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	tx, _, err := r.readTransaction(*in)
+	if err != nil {
+		return err
+	}
+
+	summary := agent.Summarize(tx)
+	signatures := signatureCount(tx)
+	if r.json {
+		return r.printJSON(struct {
+			agent.Summary
+			Signatures int `json:"signatures"`
+		}{summary, signatures})
+	}
+	printSummary(r.stdout, summary)
+	fmt.Fprintf(r.stdout, "Signatures:     %d\n", signatures)
+	return nil
+}
+
+func (r *runner) txSign(args []string) error {
+	fs := r.newFlagSet("tx sign")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	addPassphraseFlags(fs, &flags.passphrase)
+	in := fs.String("in", "-", "transaction file, or - for stdin")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	var output txOutputFlags
+	addTxOutputFlags(fs, &output)
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	if *in == "-" && !*yes {
+		return fail(ExitUsage, errors.New("confirmation is read from stdin; pass the transaction with --in or use --yes"))
+	}
+
+	tx, _, err := r.readTransaction(*in)
+	if err != nil {
+		return err
+	}
+	summary := agent.Summarize(tx)
+	printSummary(r.stderr, summary)
+
+	s, secret, err := r.unlock(flags)
+	if err != nil {
+		return err
+	}
+	defer s.client.Close()
+
+	key, err := secret.PrivateKeyAt(s.index)
+	secret.Wipe()
+	if err != nil {
+		return err
+	}
+
+	// Offline the account cannot be looked up, but a payer that differs from
+	// the one in the metadata usually means the wrong wallet was chosen.
+	if accountID := s.account().AccountID; isAccountID(accountID) && summary.Payer != accountID {
+		fmt.Fprintf(r.stderr, "shred: warning: the payer %s is not this wallet's account %s\n", summary.Payer, accountID)
+	}
+	if !summary.ValidUntil.IsZero() && !time.Now().Before(summary.ValidUntil) {
+		fmt.Fprintf(r.stderr, "shred: warning: the transaction expired at %s and will be rejected; build it again\n", summary.ValidUntil.Format(time.RFC3339))
+	}
+	if fee, err := sdk.TransactionGetMaxTransactionFee(tx); err == nil {
+		if err := s.client.CheckFee(fee); err != nil {
+			return fail(ExitError, err)
+		}
+	}
+
+	if !*yes && !r.confirm("Sign this transaction?") {
+		return fail(ExitError, errors.New("cancelled"))
+	}
+
+	signed, err := sdk.TransactionSign(tx, key)
+	if err != nil {
+		return err
+	}
+	data, err := sdk.TransactionToBytes(signed)
+	if err != nil {
+		return err
+	}
+	return r.writeTransaction(signed, data, output)
+}
+
+func (r *runner) txSubmit(args []string) error {
+	fs := r.newFlagSet("tx submit")
+	var flags walletFlags
+	addWalletFlags(fs, &flags)
+	network := fs.String("network", "", "network to submit to (default: the wallet's network)")
+	in := fs.String("in", "-", "transaction file, or - for stdin")
+	if err := r.parse(fs, args); err != nil {
+		return err
+	}
+
+	tx, _, err := r.readTransaction(*in)
+	if err != nil {
+		return err
+	}
+	if signatureCount(tx) == 0 {
+		return fail(ExitUsage, errors.New("the transaction is not signed; sign it with \"shred tx sign\" first"))
+	}
+
+	client, _, err := r.txClient(flags, *network, "-")
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	txID, err := client.Submit(tx)
+	if err != nil {
+		return fail(ExitNetwork, err)
+	}
+
+	if r.json {
+		return r.printJSON(struct {
+			TransactionID string `json:"transaction_id"`
+		}{txID})
+	}
+	fmt.Fprintf(r.stdout, "Submitted\nTransaction ID: %s\n", txID)
+	return nil
+}
+
+// txClient connects to the named network, or to the wallet's network when
+// none is given. The payer is from, or the wallet's account; "-" skips the
+// lookup.
+func (r *runner) txClient(flags walletFlags, network, from string) (*hedera.Client, string, error) {
+	if from != "" && from != "-" {
+		if _, err := sdk.AccountIDFromString(from); err != nil {
+			return nil, "", fail(ExitUsage, fmt.Errorf("invalid payer: %w", err))
+		}
+	}
+
+	if network != "" {
+		if !slices.Contains(hedera.Networks, network) {
+			return nil, "", fail(ExitUsage, fmt.Errorf("unknown network %q", network))
+		}
+		if from == "" {
+			return nil, "", fail(ExitUsage, errors.New("--from is required with --network"))
+		}
+		client, err := hedera.NewClient(hedera.NetworkConfig{Name: network})
+		return client, from, err
+	}
+
+	s, err := r.openReadOnly(flags)
+	if err != nil {
+		return nil, "", err
+	}
+	if from != "" {
+		return s.client, from, nil
+	}
+	accountID, err := s.accountID(nil)
+	if err != nil {
+		s.client.Close()
+		return nil, "", err
+	}
+	return s.client, accountID, nil
+}
+
+func (r *runner) readTransaction(path string) (sdk.TransactionInterface, []byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(r.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, nil, fail(ExitNotFound, fmt.Errorf("failed to read transaction: %w", err))
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data))); err == nil {
+		data = decoded
+	}
+
+	tx, err := sdk.TransactionFromBytes(data)
+	if err != nil {
+		return nil, nil, fail(ExitUsage, fmt.Errorf("not a valid transaction: %w", err))
+	}
+	return tx, data, nil
+}
+
+func (r *runner) writeTransaction(tx sdk.TransactionInterface, data []byte, output txOutputFlags) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if output.qr && len(encoded) > maxQRBytes {
+		return fail(ExitUsage, fmt.Errorf("the transaction is too large for a QR code (%d bytes); use --out", len(encoded)))
+	}
+
+	if output.out != "" {
+		if err := os.WriteFile(output.out, data, 0600); err != nil {
+			return fmt.Errorf("failed to write transaction: %w", err)
+		}
+	}
+
+	txID := ""
+	if id, err := sdk.TransactionGetTransactionID(tx); err == nil {
+		txID = id.String()
+	}
+
+	switch {
+	case r.json:
+		if err := r.printJSON(txJSON{TransactionID: txID, Transaction: encoded}); err != nil {
+			return err
+		}
+	case output.out != "":
+		fmt.Fprintf(r.stdout, "Wrote %s\nTransaction ID: %s\n", output.out, txID)
+	default:
+		fmt.Fprintln(r.stdout, encoded)
+	}
+
+	if output.qr {
+		qrterminal.GenerateWithConfig(encoded, qrterminal.Config{
+			Level:     qrterminal.L,
+			Writer:    r.stderr,
+			BlackChar: qrterminal.BLACK,
+			WhiteChar: qrterminal.WHITE,
+			QuietZone: 1,
+		})
+	}
+	return nil
+}
+
+func printSummary(w io.Writer, summary agent.Summary) {
+	memo := summary.Memo
+	if memo == "" {
+		memo = "(none)"
+	}
+	fmt.Fprintf(w, "Type:           %s\n", summary.Type)
+	fmt.Fprintf(w, "Transaction ID: %s\n", summary.TransactionID)
+	fmt.Fprintf(w, "Payer:          %s\n", summary.Payer)
+	fmt.Fprintf(w, "Max Fee:        %s\n", summary.MaxFee)
+	fmt.Fprintf(w, "Memo:           %s\n", memo)
+	if !summary.ValidStart.IsZero() {
+		fmt.Fprintf(w, "Valid:          %s until %s%s\n", summary.ValidStart.Format(time.RFC3339), summary.ValidUntil.Format(time.RFC3339), validityNote(summary, time.Now()))
+	}
+	for _, transfer := range summary.HbarTransfers {
+		fmt.Fprintf(w, "  %-14s %s\n", transfer.Account, sdk.HbarFromTinybar(transfer.Tinybars).String())
+	}
+	for _, transfer := range summary.TokenTransfers {
+		fmt.Fprintf(w, "  %-14s %d of %s\n", transfer.Account, transfer.Amount, transfer.TokenID)
+	}
+	if summary.NFTTransfers > 0 {
+		fmt.Fprintf(w, "  %d NFT transfer(s)\n", summary.NFTTransfers)
+	}
+}
+
+// validityNote says where now falls in the transaction's valid window.
+func validityNote(summary agent.Summary, now time.Time) string {
+	switch {
+	case !now.Before(summary.ValidUntil):
+		return " (expired)"
+	case now.Before(summary.ValidStart):
+		return fmt.Sprintf(" (starts in %s)", summary.ValidStart.Sub(now).Round(time.Second))
+	default:
+		return fmt.Sprintf(" (expires in %s)", summary.ValidUntil.Sub(now).Round(time.Second))
+	}
+}
+
+func signatureCount(tx sdk.TransactionInterface) int {
+	signatures, err := sdk.TransactionGetSignatures(tx)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, bySigner := range signatures {
+		count = max(count, len(bySigner))
+	}
+	return count
+}
+
+// parseValidStart accepts a delay from now or an absolute time, and starts
+// defaultValidStartDelay from now when value is empty.
+func parseValidStart(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().Add(defaultValidStartDelay), nil
+	}
+	if delay, err := time.ParseDuration(value); err == nil && delay >= 0 {
+		return time.Now().Add(delay), nil
+	}
+	if start, err := time.Parse(time.RFC3339, value); err == nil {
+		return start, nil
+	}
+	return time.Time{}, fail(ExitUsage, fmt.Errorf("invalid --valid-start %q; use a delay like 30m or an RFC 3339 time", value))
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+type walletFlags struct {
+	wallet     string
+	account    int
+	passphrase passphraseFlags
+}
+
+func addWalletFlags(fs *flag.FlagSet, flags *walletFlags) {
+	fs.StringVar(&flags.wallet, "wallet", "", "wallet number, file name, account ID or EVM address")
+	fs.IntVar(&flags.account, "account", -1, "HD account index (default: the wallet's active account)")
+}
+
+// session is an opened wallet: its metadata, the selected HD account and a
+// client for the wallet's network.
+type session struct {
+	wallet   crypto.WalletInfo
+	metadata crypto.WalletMetadata
+	index    uint32
+	client   *hedera.Client
+}
+
+func findWallet(query string) (crypto.WalletInfo, error) {
+	wallets, err := crypto.ListWallets()
+	if err != nil {
+		return crypto.WalletInfo{}, err
+	}
+	if len(wallets) == 0 {
+		return crypto.WalletInfo{}, fail(ExitNotFound, errors.New("no wallets found; create one with the interactive wallet first"))
+	}
+
+	if query == "" {
+		if len(wallets) > 1 {
+			return crypto.WalletInfo{}, fail(ExitUsage, errors.New(`several wallets exist; choose one with --wallet (see "shred wallet list")`))
+		}
+		return wallets[0], nil
+	}
+
+	if number, err := strconv.Atoi(query); err == nil {
+		if number < 1 || number > len(wallets) {
+			return crypto.WalletInfo{}, fail(ExitNotFound, fmt.Errorf("no wallet number %d", number))
+		}
+		return wallets[number-1], nil
+	}
+
+	needle := strings.TrimPrefix(strings.ToLower(query), "0x")
+	for _, wallet := range wallets {
+		switch {
+		case wallet.FileName == query, wallet.FilePath == query, filepath.Base(query) == wallet.FileName:
+			return wallet, nil
+		case wallet.AccountID != "" && wallet.AccountID == query:
+			return wallet, nil
+		case wallet.EVMAddress != "" && strings.TrimPrefix(strings.ToLower(wallet.EVMAddress), "0x") == needle:
+			return wallet, nil
+		}
+	}
+	return crypto.WalletInfo{}, fail(ExitNotFound, fmt.Errorf("no wallet matches %q", query))
+}
+
+// openReadOnly opens a wallet without its passphrase. The metadata is not
+// authenticated, which is acceptable for showing balances and history.
+func (r *runner) openReadOnly(flags walletFlags) (*session, error) {
+	wallet, err := findWallet(flags.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := crypto.LoadWalletMetadata(wallet.FilePath)
+	if err != nil {
+		return nil, fail(ExitNotFound, fmt.Errorf("wallet metadata is unavailable; unlock the wallet once in the interactive wallet: %w", err))
+	}
+	return newSession(wallet, metadata, flags)
+}
+
+// unlock decrypts the wallet and verifies its metadata. The caller must
+// Wipe the returned secret.
+func (r *runner) unlock(flags walletFlags) (*session, *crypto.WalletSecret, error) {
+	wallet, err := findWallet(flags.wallet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	passphrase, err := r.readPassphrase(flags.passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := crypto.LoadWallet(passphrase, wallet.FilePath)
+	wipe(passphrase)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidPassphrase) {
+			return nil, nil, fail(ExitAuth, err)
+		}
+		return nil, nil, err
+	}
+
+	metadata, err := crypto.LoadVerifiedWalletMetadata(wallet.FilePath, secret)
+	if err != nil {
+		secret.Wipe()
+		if errors.Is(err, crypto.ErrMetadataTampered) {
+			return nil, nil, fail(ExitAuth, err)
+		}
+		return nil, nil, err
+	}
+
+	// Unlocking from the CLI never writes the wallet file. Upgrading it is
+	// left to the interactive wallet, which says when it does.
+	if secret.NeedsUpgrade() {
+		fmt.Fprintf(r.stderr, "shred: warning: %s uses an older wallet format; unlock it once in the interactive wallet to upgrade it\n", wallet.FilePath)
+	}
+
+	s, err := newSession(wallet, metadata, flags)
+	if err != nil {
+		secret.Wipe()
+		return nil, nil, err
+	}
+	if s.index != 0 && !secret.SupportsAccounts() {
+		secret.Wipe()
+		return nil, nil, fail(ExitUsage, errors.New("this wallet only has a single account"))
+	}
+	return s, secret, nil
+}
+
+func newSession(wallet crypto.WalletInfo, metadata crypto.WalletMetadata, flags walletFlags) (*session, error) {
+	index := metadata.ActiveAccount
+	if flags.account >= 0 {
+		index = uint32(flags.account)
+	}
+
+	client, err := hedera.NewClient(metadata.NetworkConfig())
+	if err != nil {
+		return nil, err
+	}
+	client.MaxFee = metadata.MaxFee()
+
+	return &session{
+		wallet:   wallet,
+		metadata: metadata,
+		index:    index,
+		client:   client,
+	}, nil
+}
+
+// account returns what the metadata knows about the selected HD account.
+// Metadata written before accounts were tracked only describes account 0.
+func (s *session) account() crypto.AccountMetadata {
+	for _, account := range s.metadata.Accounts {
+		if account.Index == s.index {
+			return account
+		}
+	}
+	if s.index == 0 {
+		return crypto.AccountMetadata{
+			EVMAddress: s.metadata.EVMAddress,
+			AccountID:  s.metadata.AccountID,
+		}
+	}
+	return crypto.AccountMetadata{Index: s.index}
+}
+
+// accountID returns the selected account's ID, asking the mirror node when
+// the metadata does not have it yet.
+func (s *session) accountID(publicKey *sdk.PublicKey) (string, error) {
+	account := s.account()
+	if isAccountID(account.AccountID) {
+		return account.AccountID, nil
+	}
+
+	var accountID string
+	var err error
+	switch {
+	case publicKey != nil:
+		accountID, err = s.client.GetAccountIDForKey(*publicKey, account.EVMAddress)
+	case account.EVMAddress != "":
+		accountID, err = s.client.GetAccountIDFromEVMAddress(account.EVMAddress)
+	}
+	if err != nil {
+		return "", fail(ExitNetwork, fmt.Errorf("failed to look up account: %w", err))
+	}
+	if accountID == "" {
+		return "", fail(ExitNotFound, fmt.Errorf("account #%d has no Hedera account yet", s.index))
+	}
+	return accountID, nil
+}
+
+func isAccountID(value string) bool {
+	return value != "" && value != "Unverified" && value != "Inactive"
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/divin3circle/shred/internal/crypto"
+	"github.com/divin3circle/shred/internal/hedera"
+)
+
+const (
+	DefaultVerifyWords       = 3
+	DefaultVerifyMaxAttempts = 3
+)
+
+// Config holds user preferences shared by every wallet. It lives in
+// config.json next to the wallet files; missing values fall back to the
+// defaults above.
+type Config struct {
+	// VerifyWords is how many randomly chosen words of a new recovery
+	// phrase (or share) must be re-entered.
+	VerifyWords int `json:"verify_words,omitempty"`
+	// VerifyFullPhrase asks for every word instead of a random sample.
+	VerifyFullPhrase bool `json:"verify_full_phrase,omitempty"`
+	// VerifyMaxAttempts is how many wrong words are allowed before the
+	// phrase is shown again.
+	VerifyMaxAttempts int `json:"verify_max_attempts,omitempty"`
+
+	// AgentAutoApprove proposes signing requests the agent may approve
+	// without asking. This file is not authenticated, so the agent asks for
+	// confirmation before it adopts changed rules into the wallet metadata;
+	// only the adopted rules are applied.
+	AgentAutoApprove []crypto.AgentRule `json:"agent_auto_approve,omitempty"`
+
+	// MemoRequiredAccounts lists custodial accounts, such as exchange
+	// deposit addresses, that credit transfers by their memo. Sending to
+	// one without a memo shows a warning.
+	MemoRequiredAccounts []string `json:"memo_required_accounts,omitempty"`
+
+	// Currency is the fiat currency balances are valued in.
+	Currency string `json:"currency,omitempty"`
+	// PriceFile is a JSON file of asset prices (see hedera.StaticPrices),
+	// relative to the wallet directory unless absolute. Without it only
+	// HBAR is valued, in USD, from the network exchange rate.
+	PriceFile string `json:"price_file,omitempty"`
+}
+
+// PriceFilePath resolves PriceFile, or returns "" when none is set.
+func (c Config) PriceFilePath() string {
+	if c.PriceFile == "" || filepath.IsAbs(c.PriceFile) {
+		return c.PriceFile
+	}
+	walletDir, err := crypto.GetWalletDirectory()
+	if err != nil {
+		return c.PriceFile
+	}
+	return filepath.Join(walletDir, c.PriceFile)
+}
+
+// RequiresMemo reports whether transfers to accountID need a memo.
+func (c Config) RequiresMemo(accountID string) bool {
+	for _, account := range c.MemoRequiredAccounts {
+		if account == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func Default() Config {
+	return Config{
+		VerifyWords:       DefaultVerifyWords,
+		VerifyMaxAttempts: DefaultVerifyMaxAttempts,
+		Currency:          hedera.DefaultCurrency,
+	}
+}
+
+func Path() (string, error) {
+	walletDir, err := crypto.GetWalletDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(walletDir, "config.json"), nil
+}
+
+func Load() (Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if cfg.VerifyWords <= 0 {
+		cfg.VerifyWords = DefaultVerifyWords
+	}
+	if cfg.VerifyMaxAttempts <= 0 {
+		cfg.VerifyMaxAttempts = DefaultVerifyMaxAttempts
+	}
+	cfg.Currency = strings.ToUpper(cfg.Currency)
+	if cfg.Currency == "" {
+		cfg.Currency = hedera.DefaultCurrency
+	}
+	return cfg, nil
+}
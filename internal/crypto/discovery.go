@@ -1,20 +1,118 @@
 package crypto
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/divin3circle/shred/internal/hedera"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
 )
 
+var ErrMetadataTampered = errors.New("wallet metadata failed its integrity check")
+
+const metadataMACLabel = "shred wallet metadata v1"
+
 type WalletMetadata struct {
-	EVMAddress string    `json:"evm_address"`
-	CreatedAt  time.Time `json:"created_at"`
-	AccountID    string            `json:"account_id,omitempty"`
-	Network      string            `json:"network"`
-	TokenAliases map[string]string `json:"token_aliases,omitempty"`
+	EVMAddress         string            `json:"evm_address"`
+	CreatedAt          time.Time         `json:"created_at"`
+	AccountID          string            `json:"account_id,omitempty"`
+	Network            string            `json:"network"`
+	KeyType            KeyType           `json:"key_type,omitempty"`
+	Derivation         string            `json:"derivation,omitempty"`
+	MnemonicPassphrase bool              `json:"mnemonic_passphrase,omitempty"`
+	NodeAddress        string            `json:"node_address,omitempty"`
+	NodeAccountID      string            `json:"node_account_id,omitempty"`
+	MirrorURL          string            `json:"mirror_url,omitempty"`
+	TokenAliases       map[string]string `json:"token_aliases,omitempty"`
+	Accounts           []AccountMetadata `json:"accounts,omitempty"`
+	ActiveAccount      uint32            `json:"active_account,omitempty"`
+	MaxFeeTinybars     int64             `json:"max_fee_tinybars,omitempty"`
+	AgentAutoApprove   []AgentRule       `json:"agent_auto_approve,omitempty"`
+	MAC                string            `json:"mac,omitempty"`
+}
+
+// AgentRule approves plain HBAR transfers from the agent's account that send
+// at most MaxTinybars, and only to Recipients when that list is not empty.
+type AgentRule struct {
+	Recipients  []string `json:"recipients,omitempty"`
+	MaxTinybars int64    `json:"max_tinybars"`
+}
+
+// AgentRulesEqual reports whether two rule lists approve the same requests
+// in the same order.
+func AgentRulesEqual(a, b []AgentRule) bool {
+	return slices.EqualFunc(a, b, func(x, y AgentRule) bool {
+		return x.MaxTinybars == y.MaxTinybars && slices.Equal(x.Recipients, y.Recipients)
+	})
+}
+
+type AccountMetadata struct {
+	Index      uint32 `json:"index"`
+	Label      string `json:"label,omitempty"`
+	EVMAddress string `json:"evm_address,omitempty"`
+	AccountID  string `json:"account_id,omitempty"`
+}
+
+func (m WalletMetadata) NetworkConfig() hedera.NetworkConfig {
+	name := m.Network
+	if name == "" {
+		name = hedera.NetworkTestnet
+	}
+	return hedera.NetworkConfig{
+		Name:          name,
+		NodeAddress:   m.NodeAddress,
+		NodeAccountID: m.NodeAccountID,
+		MirrorURL:     m.MirrorURL,
+	}
+}
+
+func (m *WalletMetadata) SetNetwork(network hedera.NetworkConfig) {
+	m.Network = network.Name
+	m.NodeAddress = network.NodeAddress
+	m.NodeAccountID = network.NodeAccountID
+	m.MirrorURL = network.MirrorURL
+}
+
+// MaxFee is the most the wallet pays in fees for a single transaction.
+func (m WalletMetadata) MaxFee() sdk.Hbar {
+	if m.MaxFeeTinybars <= 0 {
+		return hedera.DefaultMaxFee
+	}
+	return sdk.HbarFromTinybar(m.MaxFeeTinybars)
+}
+
+// Account returns the entry for an HD account index, adding it if needed.
+func (m *WalletMetadata) Account(index uint32) *AccountMetadata {
+	for i := range m.Accounts {
+		if m.Accounts[i].Index == index {
+			return &m.Accounts[i]
+		}
+	}
+	m.Accounts = append(m.Accounts, AccountMetadata{Index: index})
+	sort.Slice(m.Accounts, func(i, j int) bool {
+		return m.Accounts[i].Index < m.Accounts[j].Index
+	})
+	return m.Account(index)
+}
+
+func (m WalletMetadata) NextAccountIndex() uint32 {
+	var next uint32
+	for _, account := range m.Accounts {
+		if account.Index >= next {
+			next = account.Index + 1
+		}
+	}
+	return next
 }
 
 type WalletInfo struct {
@@ -47,13 +145,19 @@ func GetMetadataPath(walletPath string) string {
 	return strings.TrimSuffix(walletPath, ".dat") + ".meta"
 }
 
-func SaveWalletMetadata(walletPath string, metadata WalletMetadata) error {
+func SaveWalletMetadata(walletPath string, metadata WalletMetadata, secret *WalletSecret) error {
+	mac, err := metadataMAC(metadata, secret)
+	if err != nil {
+		return err
+	}
+	metadata.MAC = mac
+
 	metaPath := GetMetadataPath(walletPath)
 	data, err := json.MarshalIndent(metadata, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
-	return os.WriteFile(metaPath, data, 0600)
+	return writeFileAtomic(metaPath, data, 0600)
 }
 
 func LoadWalletMetadata(walletPath string) (WalletMetadata, error) {
@@ -71,6 +175,55 @@ func LoadWalletMetadata(walletPath string) (WalletMetadata, error) {
 	return metadata, nil
 }
 
+// LoadVerifiedWalletMetadata checks the metadata MAC against the unlocked
+// secret. Wallets written before metadata was authenticated are accepted
+// unsigned once; they are signed on the next save. A missing or unsigned
+// file for a newer wallet is reported as ErrMetadataTampered.
+func LoadVerifiedWalletMetadata(walletPath string, secret *WalletSecret) (WalletMetadata, error) {
+	metadata, err := LoadWalletMetadata(walletPath)
+	if err != nil {
+		if secret.metadataRequired {
+			return WalletMetadata{}, fmt.Errorf("%w: %v", ErrMetadataTampered, err)
+		}
+		return WalletMetadata{}, err
+	}
+
+	if metadata.MAC == "" && !secret.metadataRequired {
+		return metadata, nil
+	}
+
+	expected, err := metadataMAC(metadata, secret)
+	if err != nil {
+		return WalletMetadata{}, err
+	}
+	if !hmac.Equal([]byte(expected), []byte(metadata.MAC)) {
+		return WalletMetadata{}, ErrMetadataTampered
+	}
+
+	return metadata, nil
+}
+
+func metadataMAC(metadata WalletMetadata, secret *WalletSecret) (string, error) {
+	if secret == nil || len(secret.Data) == 0 {
+		return "", errors.New("wallet is locked")
+	}
+
+	keyMAC := hmac.New(sha256.New, secret.Data)
+	keyMAC.Write([]byte(metadataMACLabel))
+	key := keyMAC.Sum(nil)
+	defer wipeBytes(key)
+
+	metadata.MAC = ""
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
 func ListWallets() ([]WalletInfo, error) {
 	walletDir, err := GetWalletDirectory()
 	if err != nil {
@@ -121,18 +274,23 @@ func ListWallets() ([]WalletInfo, error) {
 				EVMAddress: "Unknown (decrypt to view)",
 				CreatedAt:  info.ModTime(),
 				AccountID:  "",
-				Network:    "testnet",
+				Network:    hedera.NetworkTestnet,
 			})
 			continue
 		}
 
+		network := metadata.Network
+		if network == "" {
+			network = hedera.NetworkTestnet
+		}
+
 		wallets = append(wallets, WalletInfo{
 			FilePath:   walletPath,
 			FileName:   entry.Name(),
 			EVMAddress: metadata.EVMAddress,
 			CreatedAt:  metadata.CreatedAt,
 			AccountID:  metadata.AccountID,
-			Network:    metadata.Network,
+			Network:    network,
 		})
 	}
 
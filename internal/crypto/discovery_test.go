@@ -0,0 +1,105 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newSignedWallet saves a current wallet with signed metadata.
+func newSignedWallet(t *testing.T, passphrase []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wallet-test.dat")
+	secret := NewMnemonicSecret([]byte(testMnemonic12), nil, KeyTypeECDSA)
+	if err := SaveWallet(secret, passphrase, path); err != nil {
+		t.Fatalf("SaveWallet: %v", err)
+	}
+	err := SaveWalletMetadata(path, WalletMetadata{
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		AccountID: "0.0.1001",
+		Network:   "testnet",
+	}, secret)
+	if err != nil {
+		t.Fatalf("SaveWalletMetadata: %v", err)
+	}
+	return path
+}
+
+// editMetadata rewrites one field of the metadata file the way someone
+// with access to the config directory could.
+func editMetadata(t *testing.T, path, field string, value any) {
+	t.Helper()
+	metaPath := GetMetadataPath(path)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if value == nil {
+		delete(fields, field)
+	} else {
+		fields[field] = value
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(metaPath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifiedMetadata(t *testing.T) {
+	passphrase := []byte("passphrase")
+	path := newSignedWallet(t, passphrase)
+
+	_, metadata, err := unlock(t, path, passphrase)
+	if err != nil {
+		t.Fatalf("LoadVerifiedWalletMetadata: %v", err)
+	}
+	if metadata.AccountID != "0.0.1001" || metadata.Network != "testnet" {
+		t.Errorf("metadata = %+v", metadata)
+	}
+}
+
+func TestEditedMetadataIsTampered(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value any
+	}{
+		{"account ID", "account_id", "0.0.6666"},
+		{"network", "network", "mainnet"},
+		{"agent rules", "agent_auto_approve", []map[string]any{{"recipients": []string{"0.0.6666"}, "max_tinybars": 100_000_000_000}}},
+		{"removed MAC", "mac", nil},
+	}
+
+	passphrase := []byte("passphrase")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := newSignedWallet(t, passphrase)
+			editMetadata(t, path, tc.field, tc.value)
+
+			if _, _, err := unlock(t, path, passphrase); !errors.Is(err, ErrMetadataTampered) {
+				t.Errorf("err = %v, want ErrMetadataTampered", err)
+			}
+		})
+	}
+}
+
+func TestMissingMetadataIsTampered(t *testing.T) {
+	passphrase := []byte("passphrase")
+	path := newSignedWallet(t, passphrase)
+	if err := os.Remove(GetMetadataPath(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := unlock(t, path, passphrase); !errors.Is(err, ErrMetadataTampered) {
+		t.Errorf("err = %v, want ErrMetadataTampered", err)
+	}
+}
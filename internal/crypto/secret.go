@@ -0,0 +1,304 @@
+package crypto
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+type SecretKind string
+
+const (
+	SecretMnemonic   SecretKind = "mnemonic"
+	SecretPrivateKey SecretKind = "private_key"
+)
+
+type KeyType string
+
+const (
+	KeyTypeECDSA   KeyType = "ecdsa_secp256k1"
+	KeyTypeED25519 KeyType = "ed25519"
+)
+
+var KeyTypes = []KeyType{KeyTypeECDSA, KeyTypeED25519}
+
+// DerivationStandard marks mnemonic secrets that use DerivePrivateKey. An
+// empty derivation means the legacy DeriveECDSAKey scheme.
+const DerivationStandard = "standard"
+
+// AutoLockAfter is how long an unlocked secret is kept without a key press
+// before the wallet, or the signing agent, wipes it.
+const AutoLockAfter = 10 * time.Minute
+
+var (
+	oidED25519     = asn1.ObjectIdentifier{1, 3, 101, 112}
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+func (k KeyType) DisplayName() string {
+	switch k {
+	case KeyTypeED25519:
+		return "ED25519"
+	default:
+		return "ECDSA (secp256k1)"
+	}
+}
+
+type WalletSecret struct {
+	Kind       SecretKind `json:"kind"`
+	KeyType    KeyType    `json:"key_type"`
+	Derivation string     `json:"derivation,omitempty"`
+	Data       []byte     `json:"data"`
+
+	// Passphrase is the optional BIP-39 mnemonic passphrase ("25th word").
+	// It is unrelated to the passphrase that encrypts the wallet file.
+	Passphrase []byte `json:"passphrase,omitempty"`
+
+	// metadataRequired is set for wallet files whose metadata has always
+	// been authenticated, so an unsigned .meta file cannot be trusted.
+	metadataRequired bool
+	// needsUpgrade is set for wallet files in an older format or with
+	// weaker KDF parameters.
+	needsUpgrade bool
+}
+
+func NewMnemonicSecret(mnemonic, passphrase []byte, keyType KeyType) *WalletSecret {
+	data := make([]byte, len(mnemonic))
+	copy(data, mnemonic)
+
+	var pass []byte
+	if len(passphrase) > 0 {
+		pass = make([]byte, len(passphrase))
+		copy(pass, passphrase)
+	}
+
+	return &WalletSecret{
+		Kind:       SecretMnemonic,
+		KeyType:    keyType,
+		Derivation: DerivationStandard,
+		Data:       data,
+		Passphrase: pass,
+	}
+}
+
+func legacyMnemonicSecret(mnemonic []byte) *WalletSecret {
+	data := make([]byte, len(mnemonic))
+	copy(data, mnemonic)
+	return &WalletSecret{
+		Kind:    SecretMnemonic,
+		KeyType: KeyTypeECDSA,
+		Data:    data,
+	}
+}
+
+func (s *WalletSecret) IsLegacyDerivation() bool {
+	return s.Kind == SecretMnemonic && s.Derivation != DerivationStandard
+}
+
+func (s *WalletSecret) DerivationName() string {
+	switch {
+	case s.Kind != SecretMnemonic:
+		return ""
+	case s.IsLegacyDerivation():
+		return "legacy"
+	default:
+		return DerivationStandard
+	}
+}
+
+func NewPrivateKeySecret(key sdk.PrivateKey, keyType KeyType) *WalletSecret {
+	return &WalletSecret{
+		Kind:    SecretPrivateKey,
+		KeyType: keyType,
+		Data:    key.BytesRaw(),
+	}
+}
+
+func (s *WalletSecret) Wipe() {
+	if s == nil {
+		return
+	}
+	for i := range s.Data {
+		s.Data[i] = 0
+	}
+	s.Data = nil
+
+	for i := range s.Passphrase {
+		s.Passphrase[i] = 0
+	}
+	s.Passphrase = nil
+}
+
+// NeedsUpgrade reports whether the wallet file this secret was loaded from
+// should be rewritten with UpgradeWallet.
+func (s *WalletSecret) NeedsUpgrade() bool {
+	return s.needsUpgrade
+}
+
+func (s *WalletSecret) HasPassphrase() bool {
+	return len(s.Passphrase) > 0
+}
+
+// SupportsAccounts reports whether more than one account can be derived.
+// Raw keys and legacy mnemonics only have account 0.
+func (s *WalletSecret) SupportsAccounts() bool {
+	return s.Kind == SecretMnemonic && !s.IsLegacyDerivation()
+}
+
+func (s *WalletSecret) PrivateKey() (sdk.PrivateKey, error) {
+	return s.PrivateKeyAt(0)
+}
+
+func (s *WalletSecret) PrivateKeyAt(index uint32) (sdk.PrivateKey, error) {
+	if index != 0 && !s.SupportsAccounts() {
+		return sdk.PrivateKey{}, errors.New("this wallet only has a single account")
+	}
+
+	switch s.Kind {
+	case SecretMnemonic:
+		if s.IsLegacyDerivation() {
+			return DeriveECDSAKey(s.Data)
+		}
+		return DerivePrivateKey(s.Data, string(s.Passphrase), s.KeyType, index)
+	case SecretPrivateKey:
+		if s.KeyType == KeyTypeED25519 {
+			return sdk.PrivateKeyFromBytesEd25519(s.Data)
+		}
+		return sdk.PrivateKeyFromBytesECDSA(s.Data)
+	default:
+		return sdk.PrivateKey{}, fmt.Errorf("unknown wallet secret kind: %s", s.Kind)
+	}
+}
+
+func encodeSecret(secret *WalletSecret) ([]byte, error) {
+	return json.Marshal(secret)
+}
+
+// decodeSecret also accepts the original payload format, which held the bare
+// mnemonic bytes.
+func decodeSecret(plaintext []byte) (*WalletSecret, error) {
+	if len(plaintext) > 0 && plaintext[0] == '{' {
+		var secret WalletSecret
+		if err := json.Unmarshal(plaintext, &secret); err != nil {
+			return nil, fmt.Errorf("failed to decode wallet secret: %w", err)
+		}
+		return &secret, nil
+	}
+
+	return legacyMnemonicSecret(plaintext), nil
+}
+
+// ParsePrivateKey accepts raw hex (with or without 0x) and DER-encoded hex as
+// exported by the Hedera portal or OpenSSL. DER keys carry their own
+// algorithm, which must agree with keyType.
+func ParsePrivateKey(input string, keyType KeyType) (sdk.PrivateKey, error) {
+	value := strings.ToLower(strings.TrimSpace(input))
+	value = strings.TrimPrefix(value, "0x")
+	if value == "" {
+		return sdk.PrivateKey{}, errors.New("private key is empty")
+	}
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return sdk.PrivateKey{}, errors.New("private key must be hex encoded")
+	}
+	defer wipeBytes(data)
+
+	raw, derType, isDER, err := parseDERPrivateKey(data)
+	if err != nil {
+		return sdk.PrivateKey{}, err
+	}
+	if isDER {
+		defer wipeBytes(raw)
+		if derType != keyType {
+			return sdk.PrivateKey{}, fmt.Errorf("DER key is %s, not %s", derType.DisplayName(), keyType.DisplayName())
+		}
+		if len(raw) != 32 {
+			return sdk.PrivateKey{}, fmt.Errorf("DER key holds %d bytes, not 32", len(raw))
+		}
+		if keyType == KeyTypeED25519 {
+			return sdk.PrivateKeyFromBytesEd25519(raw)
+		}
+		return sdk.PrivateKeyFromBytesECDSA(raw)
+	}
+
+	switch keyType {
+	case KeyTypeED25519:
+		return sdk.PrivateKeyFromStringEd25519(value)
+	case KeyTypeECDSA:
+		return sdk.PrivateKeyFromStringECDSA(value)
+	default:
+		return sdk.PrivateKey{}, fmt.Errorf("unknown key type: %s", keyType)
+	}
+}
+
+// sec1PrivateKey is an elliptic curve private key as in RFC 5915.
+type sec1PrivateKey struct {
+	Version    int
+	PrivateKey []byte
+	Curve      asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey  asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// parseDERPrivateKey reads a DER private key from the first byte of data:
+// PKCS#8 for ED25519 or secp256k1, including the short secp256k1 form the
+// Hedera portal exports, or an SEC1 secp256k1 key. isDER is false when data
+// is not one of these, in which case it is a raw key.
+func parseDERPrivateKey(data []byte) (raw []byte, keyType KeyType, isDER bool, err error) {
+	var pkcs8 struct {
+		Version    int
+		Algorithm  pkix.AlgorithmIdentifier
+		PrivateKey []byte
+		Attributes asn1.RawValue `asn1:"optional,tag:0"`
+		PublicKey  asn1.RawValue `asn1:"optional,tag:1"`
+	}
+	if rest, err := asn1.Unmarshal(data, &pkcs8); err == nil && len(rest) == 0 {
+		algorithm := pkcs8.Algorithm.Algorithm
+		switch {
+		case algorithm.Equal(oidED25519), algorithm.Equal(oidSecp256k1):
+			keyType = KeyTypeECDSA
+			if algorithm.Equal(oidED25519) {
+				keyType = KeyTypeED25519
+			}
+			if rest, err := asn1.Unmarshal(pkcs8.PrivateKey, &raw); err != nil || len(rest) != 0 {
+				return nil, keyType, true, errors.New("DER key is malformed")
+			}
+			return raw, keyType, true, nil
+		case algorithm.Equal(oidECPublicKey):
+			var curve asn1.ObjectIdentifier
+			if _, err := asn1.Unmarshal(pkcs8.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+				return nil, KeyTypeECDSA, true, errors.New("DER key is not on the secp256k1 curve")
+			}
+			var key sec1PrivateKey
+			if rest, err := asn1.Unmarshal(pkcs8.PrivateKey, &key); err != nil || len(rest) != 0 {
+				return nil, KeyTypeECDSA, true, errors.New("DER key is malformed")
+			}
+			return key.PrivateKey, KeyTypeECDSA, true, nil
+		default:
+			return nil, "", true, fmt.Errorf("DER key algorithm %s is not supported", algorithm)
+		}
+	}
+
+	var key sec1PrivateKey
+	if rest, err := asn1.Unmarshal(data, &key); err == nil && len(rest) == 0 && key.Version == 1 {
+		if len(key.Curve) > 0 && !key.Curve.Equal(oidSecp256k1) {
+			return nil, KeyTypeECDSA, true, errors.New("DER key is not on the secp256k1 curve")
+		}
+		return key.PrivateKey, KeyTypeECDSA, true, nil
+	}
+	return nil, "", false, nil
+}
+
+func WalletIdentifier(key sdk.PrivateKey, keyType KeyType) string {
+	if keyType == KeyTypeED25519 {
+		return key.PublicKey().StringRaw()
+	}
+	return strings.TrimPrefix(key.PublicKey().ToEvmAddress(), "0x")
+}
@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	const (
+		ecdsaKey   = "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727"
+		ed25519Key = "523f9ff611ac02e8e188617750e7e50feabd2ef9ee9b218a5c8ce2693275361d"
+		// A secp256k1 key whose bytes contain the ED25519 OID, 2b6570.
+		oidLikeKey = "2b65702c412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b72"
+		// A raw key that starts like a DER sequence.
+		sequenceLikeKey = "302e020100300506032b6570042204200000000000000000000000000000000a"
+	)
+
+	tests := []struct {
+		name    string
+		input   string
+		keyType KeyType
+		want    string
+	}{
+		{"raw ECDSA", ecdsaKey, KeyTypeECDSA, ecdsaKey},
+		{"raw ECDSA with 0x", "0x" + strings.ToUpper(ecdsaKey), KeyTypeECDSA, ecdsaKey},
+		{"raw ED25519", ed25519Key, KeyTypeED25519, ed25519Key},
+		{"raw key that looks like DER", sequenceLikeKey, KeyTypeECDSA, sequenceLikeKey},
+		{"Hedera portal ECDSA", "3030020100300706052b8104000a04220420" + ecdsaKey, KeyTypeECDSA, ecdsaKey},
+		{"Hedera portal ECDSA with OID bytes", "3030020100300706052b8104000a04220420" + oidLikeKey, KeyTypeECDSA, oidLikeKey},
+		{"PKCS#8 ED25519", "302e020100300506032b657004220420" + ed25519Key, KeyTypeED25519, ed25519Key},
+		{"PKCS#8 ECDSA", "303e020100301006072a8648ce3d020106052b8104000a04273025020101" + "0420" + ecdsaKey, KeyTypeECDSA, ecdsaKey},
+		{"SEC1 ECDSA", "302e0201010420" + ecdsaKey + "a00706052b8104000a", KeyTypeECDSA, ecdsaKey},
+	}
+
+	for _, tc := range tests {
+		key, err := ParsePrivateKey(tc.input, tc.keyType)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if got := key.StringRaw(); got != tc.want {
+			t.Errorf("%s: key = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestParsePrivateKeyRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		keyType KeyType
+	}{
+		{"empty", " ", KeyTypeECDSA},
+		{"not hex", "xyz", KeyTypeECDSA},
+		{"ED25519 DER as ECDSA", "302e020100300506032b657004220420" + strings.Repeat("11", 32), KeyTypeECDSA},
+		{"ECDSA DER as ED25519", "3030020100300706052b8104000a04220420" + strings.Repeat("11", 32), KeyTypeED25519},
+		{"P-256 DER", "3041020100301306072a8648ce3d020106082a8648ce3d030107042730250201010420" + strings.Repeat("11", 32), KeyTypeECDSA},
+		{"short DER key", "302d020100300506032b65700421041f" + strings.Repeat("11", 31), KeyTypeED25519},
+	}
+
+	for _, tc := range tests {
+		if _, err := ParsePrivateKey(tc.input, tc.keyType); err == nil {
+			t.Errorf("%s: ParsePrivateKey accepted %q", tc.name, tc.input)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Recovery phrase shares use Shamir's secret sharing over GF(2^8) with the
+// AES polynomial x^8 + x^4 + x^3 + x + 1. The shared secret is the mnemonic
+// entropy followed by a 4-byte tag, the first 4 bytes of HMAC-SHA256 keyed
+// with the entropy over the split's identifier. It is split byte by byte;
+// share i holds f(i) for a random polynomial f of degree threshold-1 with
+// f(0) equal to the secret byte. The tag is only known once the shares are
+// recombined, where it confirms that the right secret came out.
+//
+// A share is serialised as
+//
+//	version (1) | threshold (1) | index (1) | identifier (4) | value (20 or 36) | checksum (2)
+//
+// where identifier is random, identical in every share of a split and
+// unrelated to the secret, and checksum is the first 2 bytes of SHA-256 over
+// everything before it. The bytes are written as 11-bit groups, most
+// significant bit first and zero padded, each mapped to a word of the
+// BIP-39 English word list. A 24-word phrase gives 33-word shares and a
+// 12-word phrase gives 22-word shares.
+const (
+	shareVersion     = 1
+	shareIDOffset    = 3
+	shareHeaderLen   = 7
+	shareTagLen      = 4
+	shareChecksumLen = 2
+)
+
+const MaxShares = 16
+
+var ErrShareMismatch = errors.New("shares do not belong to the same recovery phrase")
+
+type Share struct {
+	Threshold int
+	Index     int
+	id        []byte
+	value     []byte
+}
+
+// SplitMnemonic splits a recovery phrase into total shares, any threshold of
+// which rebuild it. Shares are returned as space separated words.
+func SplitMnemonic(mnemonic []byte, threshold, total int) ([]string, error) {
+	if threshold < 2 || threshold > total {
+		return nil, fmt.Errorf("threshold must be between 2 and %d", total)
+	}
+	if total > MaxShares {
+		return nil, fmt.Errorf("at most %d shares are supported", MaxShares)
+	}
+
+	entropy, err := bip39.EntropyFromMnemonic(string(mnemonic))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recovery phrase: %w", err)
+	}
+	defer wipeBytes(entropy)
+
+	id := make([]byte, shareHeaderLen-shareIDOffset)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate share identifier: %w", err)
+	}
+	secret := append(append([]byte(nil), entropy...), shareTag(entropy, id)...)
+	defer wipeBytes(secret)
+
+	values := make([][]byte, total)
+	for i := range values {
+		values[i] = make([]byte, len(secret))
+	}
+
+	coefficients := make([]byte, threshold)
+	defer wipeBytes(coefficients)
+	for b, secretByte := range secret {
+		coefficients[0] = secretByte
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("failed to generate share polynomial: %w", err)
+		}
+		for i := range values {
+			values[i][b] = gfEvaluate(coefficients, byte(i+1))
+		}
+	}
+
+	shares := make([]string, total)
+	for i, value := range values {
+		shares[i] = encodeShare(Share{Threshold: threshold, Index: i + 1, id: id, value: value})
+		wipeBytes(value)
+	}
+	return shares, nil
+}
+
+// shareTag authenticates the split's identifier with the entropy, so a
+// wrong combination of shares is caught after recombining.
+func shareTag(entropy, id []byte) []byte {
+	mac := hmac.New(sha256.New, entropy)
+	mac.Write(id)
+	return mac.Sum(nil)[:shareTagLen]
+}
+
+// ParseShare decodes a share and checks its checksum.
+func ParseShare(input string) (Share, error) {
+	words := strings.Fields(strings.ToLower(input))
+	if len(words) == 0 {
+		return Share{}, errors.New("share is empty")
+	}
+
+	var dataLen int
+	switch len(words) {
+	case shareWordCount(16 + shareTagLen):
+		dataLen = shareHeaderLen + 16 + shareTagLen + shareChecksumLen
+	case shareWordCount(32 + shareTagLen):
+		dataLen = shareHeaderLen + 32 + shareTagLen + shareChecksumLen
+	default:
+		return Share{}, fmt.Errorf("a share has %d or %d words (got %d)", shareWordCount(16+shareTagLen), shareWordCount(32+shareTagLen), len(words))
+	}
+
+	var bits []bool
+	for i, word := range words {
+		index, ok := bip39.GetWordIndex(word)
+		if !ok {
+			return Share{}, fmt.Errorf("word %d (%q) is not in the BIP-39 word list", i+1, word)
+		}
+		for bit := 10; bit >= 0; bit-- {
+			bits = append(bits, index&(1<<bit) != 0)
+		}
+	}
+
+	data := make([]byte, dataLen)
+	for i := range data {
+		for bit := 0; bit < 8; bit++ {
+			if bits[i*8+bit] {
+				data[i] |= 1 << (7 - bit)
+			}
+		}
+	}
+	for _, padding := range bits[dataLen*8:] {
+		if padding {
+			return Share{}, errors.New("share checksum does not match; check the words")
+		}
+	}
+
+	body := data[:len(data)-shareChecksumLen]
+	sum := sha256.Sum256(body)
+	if !bytes.Equal(sum[:shareChecksumLen], data[len(body):]) {
+		return Share{}, errors.New("share checksum does not match; check the words")
+	}
+	if body[0] != shareVersion {
+		return Share{}, fmt.Errorf("unsupported share version: %d", body[0])
+	}
+
+	share := Share{
+		Threshold: int(body[1]),
+		Index:     int(body[2]),
+		id:        body[shareIDOffset:shareHeaderLen],
+		value:     body[shareHeaderLen:],
+	}
+	if share.Threshold < 2 || share.Index < 1 || share.Index > MaxShares {
+		return Share{}, errors.New("share header is invalid")
+	}
+	return share, nil
+}
+
+// CombineShares rebuilds the recovery phrase from at least threshold shares.
+func CombineShares(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares provided")
+	}
+
+	first := shares[0]
+	if len(shares) < first.Threshold {
+		return nil, fmt.Errorf("%d shares are required (got %d)", first.Threshold, len(shares))
+	}
+	shares = shares[:first.Threshold]
+
+	seen := make(map[int]bool)
+	for _, share := range shares {
+		if share.Threshold != first.Threshold || len(share.value) != len(first.value) || !bytes.Equal(share.id, first.id) {
+			return nil, ErrShareMismatch
+		}
+		if seen[share.Index] {
+			return nil, fmt.Errorf("share #%d was entered twice", share.Index)
+		}
+		seen[share.Index] = true
+	}
+
+	secret := make([]byte, len(first.value))
+	defer wipeBytes(secret)
+	for b := range secret {
+		var secretByte byte
+		for i, share := range shares {
+			xi := byte(share.Index)
+			basis := byte(1)
+			for j, other := range shares {
+				if i == j {
+					continue
+				}
+				xj := byte(other.Index)
+				basis = gfMul(basis, gfDiv(xj, xj^xi))
+			}
+			secretByte ^= gfMul(share.value[b], basis)
+		}
+		secret[b] = secretByte
+	}
+
+	entropy, tag := secret[:len(secret)-shareTagLen], secret[len(secret)-shareTagLen:]
+	if !hmac.Equal(tag, shareTag(entropy, first.id)) {
+		return nil, ErrShareMismatch
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(mnemonic), nil
+}
+
+func encodeShare(share Share) string {
+	data := []byte{shareVersion, byte(share.Threshold), byte(share.Index)}
+	data = append(data, share.id...)
+	data = append(data, share.value...)
+	sum := sha256.Sum256(data)
+	data = append(data, sum[:shareChecksumLen]...)
+	defer wipeBytes(data)
+
+	wordList := bip39.GetWordList()
+	words := make([]string, shareWordCount(len(share.value)))
+	for w := range words {
+		index := 0
+		for bit := 0; bit < 11; bit++ {
+			pos := w*11 + bit
+			index <<= 1
+			if pos < len(data)*8 && data[pos/8]&(1<<(7-pos%8)) != 0 {
+				index |= 1
+			}
+		}
+		words[w] = wordList[index]
+	}
+	return strings.Join(words, " ")
+}
+
+func shareWordCount(valueLen int) int {
+	return ((shareHeaderLen+valueLen+shareChecksumLen)*8 + 10) / 11
+}
+
+var gfExp, gfLog = gfTables()
+
+func gfTables() ([510]byte, [256]byte) {
+	var exp [510]byte
+	var log [256]byte
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		exp[i+255] = x
+		log[x] = byte(i)
+		// multiply by the generator 3
+		x ^= gfMulSlow(x, 2)
+	}
+	return exp, log
+}
+
+func gfMulSlow(a, b byte) byte {
+	var product byte
+	for b > 0 {
+		if b&1 != 0 {
+			product ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return product
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+func gfEvaluate(coefficients []byte, x byte) byte {
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+	return result
+}
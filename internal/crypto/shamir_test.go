@@ -0,0 +1,249 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	testMnemonic12 = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testMnemonic24 = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"
+)
+
+func TestGFArithmetic(t *testing.T) {
+	// Products from FIPS-197, section 4.2.
+	for _, tc := range []struct{ a, b, want byte }{
+		{0x57, 0x83, 0xc1},
+		{0x57, 0x13, 0xfe},
+		{0x53, 0xca, 0x01},
+		{0x00, 0x83, 0x00},
+		{0x01, 0x83, 0x83},
+	} {
+		if got := gfMul(tc.a, tc.b); got != tc.want {
+			t.Errorf("gfMul(%#x, %#x) = %#x, want %#x", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b++ {
+			if got, want := gfMul(byte(a), byte(b)), gfMulSlow(byte(a), byte(b)); got != want {
+				t.Fatalf("gfMul(%#x, %#x) = %#x, gfMulSlow gives %#x", a, b, got, want)
+			}
+		}
+		if a != 0 {
+			if got := gfMul(byte(a), gfDiv(1, byte(a))); got != 1 {
+				t.Fatalf("%#x * 1/%#x = %#x, want 1", a, a, got)
+			}
+		}
+	}
+}
+
+// The shares, with identifier 01020304, of the all-zero entropy and its tag
+// on the line f(x) = secret + x, encoded by an independent implementation of
+// the share format.
+var (
+	knownShareID = []byte{1, 2, 3, 4}
+	knownShares  = []string{
+		"absurd avoid doctor advice core above advice cage absurd amount doctor acoustic avoid letter advice cage abuse detail possible palm amateur abandon",
+		"absurd avoid leopard advice core absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acquire cattle hair oil ghost abandon",
+	}
+)
+
+func TestShareKnownAnswer(t *testing.T) {
+	secret := append(make([]byte, 16), shareTag(make([]byte, 16), knownShareID)...)
+	if got := fmt.Sprintf("%x", secret[16:]); got != "268ba34e" {
+		t.Errorf("tag = %s, want 268ba34e", got)
+	}
+	for i, want := range knownShares {
+		index := i + 1
+		value := make([]byte, len(secret))
+		for b := range value {
+			value[b] = secret[b] ^ byte(index)
+		}
+		got := encodeShare(Share{Threshold: 2, Index: index, id: knownShareID, value: value})
+		if got != want {
+			t.Errorf("share %d encodes as\n%s\nwant\n%s", index, got, want)
+		}
+	}
+
+	var shares []Share
+	for _, words := range knownShares {
+		share, err := ParseShare(words)
+		if err != nil {
+			t.Fatalf("ParseShare: %v", err)
+		}
+		shares = append(shares, share)
+	}
+	mnemonic, err := CombineShares(shares)
+	if err != nil {
+		t.Fatalf("CombineShares: %v", err)
+	}
+	if string(mnemonic) != testMnemonic12 {
+		t.Errorf("combined phrase = %q, want %q", mnemonic, testMnemonic12)
+	}
+}
+
+func parseShares(t *testing.T, words []string) []Share {
+	t.Helper()
+	shares := make([]Share, len(words))
+	for i, w := range words {
+		share, err := ParseShare(w)
+		if err != nil {
+			t.Fatalf("ParseShare(share %d): %v", i+1, err)
+		}
+		shares[i] = share
+	}
+	return shares
+}
+
+// subsets returns every subset of size k of shares, in order.
+func subsets(shares []Share, k int) [][]Share {
+	if k == 0 {
+		return [][]Share{nil}
+	}
+	var result [][]Share
+	for i := 0; i+k <= len(shares); i++ {
+		for _, rest := range subsets(shares[i+1:], k-1) {
+			result = append(result, append([]Share{shares[i]}, rest...))
+		}
+	}
+	return result
+}
+
+func TestSplitCombineEverySubset(t *testing.T) {
+	for _, mnemonic := range []string{testMnemonic12, testMnemonic24} {
+		for _, split := range []struct{ threshold, total int }{{2, 2}, {2, 3}, {3, 5}, {4, 6}} {
+			words, err := SplitMnemonic([]byte(mnemonic), split.threshold, split.total)
+			if err != nil {
+				t.Fatalf("SplitMnemonic(%d of %d): %v", split.threshold, split.total, err)
+			}
+			wantWords := 22
+			if strings.Count(mnemonic, " ") == 23 {
+				wantWords = 33
+			}
+			if n := len(strings.Fields(words[0])); n != wantWords {
+				t.Errorf("share has %d words, want %d", n, wantWords)
+			}
+
+			shares := parseShares(t, words)
+			for k := split.threshold; k <= split.total; k++ {
+				for _, subset := range subsets(shares, k) {
+					got, err := CombineShares(subset)
+					if err != nil {
+						t.Fatalf("%d of %d: CombineShares(%d shares): %v", split.threshold, split.total, k, err)
+					}
+					if string(got) != mnemonic {
+						t.Fatalf("%d of %d: combined phrase = %q", split.threshold, split.total, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCombineRejectsTooFewShares(t *testing.T) {
+	words, err := SplitMnemonic([]byte(testMnemonic24), 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := parseShares(t, words)
+	for _, subset := range subsets(shares, 2) {
+		if _, err := CombineShares(subset); err == nil {
+			t.Fatal("CombineShares accepted fewer shares than the threshold")
+		}
+	}
+	if _, err := CombineShares(nil); err == nil {
+		t.Error("CombineShares accepted no shares")
+	}
+}
+
+func TestCombineRejectsDuplicateShares(t *testing.T) {
+	words, err := SplitMnemonic([]byte(testMnemonic12), 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := parseShares(t, words)
+	_, err = CombineShares([]Share{shares[0], shares[1], shares[0]})
+	if err == nil || !strings.Contains(err.Error(), "twice") {
+		t.Errorf("CombineShares with a repeated share: err = %v", err)
+	}
+}
+
+func TestCombineRejectsSharesOfDifferentPhrases(t *testing.T) {
+	first, err := SplitMnemonic([]byte(testMnemonic12), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SplitMnemonic([]byte("legal winner thank year wave sausage worth useful legal winner thank yellow"), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := parseShares(t, []string{first[0], second[1]})
+	if _, err := CombineShares(shares); !errors.Is(err, ErrShareMismatch) {
+		t.Errorf("CombineShares across phrases: err = %v, want ErrShareMismatch", err)
+	}
+}
+
+// Shares only identify their split; two splits of the same phrase do not
+// share anything, and neither can be mixed with the other.
+func TestSplitsHaveUnrelatedIdentifiers(t *testing.T) {
+	first, err := SplitMnemonic([]byte(testMnemonic12), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SplitMnemonic([]byte(testMnemonic12), 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := parseShares(t, first), parseShares(t, second)
+	if bytes.Equal(a[0].id, b[0].id) {
+		t.Error("two splits of the same phrase have the same identifier")
+	}
+	if _, err := CombineShares([]Share{a[0], b[1]}); !errors.Is(err, ErrShareMismatch) {
+		t.Errorf("CombineShares across splits: err = %v, want ErrShareMismatch", err)
+	}
+}
+
+// A share that passes its checksum can still carry the wrong value, e.g.
+// from a buggy or malicious encoder. The tag catches it after recombining.
+func TestCombineChecksTheTag(t *testing.T) {
+	shares := parseShares(t, knownShares)
+	for b := range shares[0].value {
+		value := append([]byte(nil), shares[0].value...)
+		value[b] ^= 0x80
+		forged, err := ParseShare(encodeShare(Share{Threshold: 2, Index: 1, id: knownShareID, value: value}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := CombineShares([]Share{forged, shares[1]}); !errors.Is(err, ErrShareMismatch) {
+			t.Errorf("byte %d changed: err = %v, want ErrShareMismatch", b, err)
+		}
+	}
+}
+
+func TestParseShareRejectsCorruption(t *testing.T) {
+	words := strings.Fields(knownShares[0])
+
+	for _, position := range []int{0, 5, len(words) - 1} {
+		corrupted := append([]string(nil), words...)
+		if corrupted[position] == "zoo" {
+			corrupted[position] = "abandon"
+		} else {
+			corrupted[position] = "zoo"
+		}
+		_, err := ParseShare(strings.Join(corrupted, " "))
+		if err == nil || !strings.Contains(err.Error(), "checksum") {
+			t.Errorf("word %d changed: err = %v, want a checksum error", position+1, err)
+		}
+	}
+
+	if _, err := ParseShare(strings.Join(words[1:], " ")); err == nil {
+		t.Error("ParseShare accepted a share with a missing word")
+	}
+	if _, err := ParseShare(strings.Replace(knownShares[0], "absurd", "notaword", 1)); err == nil {
+		t.Error("ParseShare accepted a word outside the word list")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -21,19 +22,102 @@ const (
 	saltLen       = 16
 )
 
+// Version 1 files predate the version field and always used legacyKDFParams.
+// Version 2 added stored KDF parameters. Version 3 binds the header to the
+// ciphertext as GCM additional data and requires authenticated metadata.
+const (
+	walletVersionLegacy  = 1
+	walletVersionKDF     = 2
+	walletVersionCurrent = 3
+	kdfArgon2id          = "argon2id"
+	maxArgon2Memory      = 4 * 1024 * 1024
+)
+
+var ErrInvalidPassphrase = errors.New("invalid passphrase or corrupted wallet file")
+
+type KDFParams struct {
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	KeyLen  uint32 `json:"key_len"`
+}
+
+var currentKDFParams = KDFParams{
+	Time:    argon2Time,
+	Memory:  argon2Memory,
+	Threads: argon2Threads,
+	KeyLen:  keyLen,
+}
+
+var legacyKDFParams = KDFParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+	KeyLen:  32,
+}
+
 type EncryptedWallet struct {
-	Salt       []byte `json:"salt"`
-	Nonce      []byte `json:"nonce"`
-	Ciphertext []byte `json:"ciphertext"`
+	Version    int        `json:"version,omitempty"`
+	KDF        string     `json:"kdf,omitempty"`
+	KDFParams  *KDFParams `json:"kdf_params,omitempty"`
+	Salt       []byte     `json:"salt"`
+	Nonce      []byte     `json:"nonce"`
+	Ciphertext []byte     `json:"ciphertext"`
+}
+
+func (w EncryptedWallet) kdfParams() (KDFParams, error) {
+	switch w.Version {
+	case 0, walletVersionLegacy:
+		return legacyKDFParams, nil
+	case walletVersionKDF, walletVersionCurrent:
+		if w.KDF != kdfArgon2id {
+			return KDFParams{}, fmt.Errorf("unsupported key derivation function: %q", w.KDF)
+		}
+		if w.KDFParams == nil {
+			return KDFParams{}, errors.New("wallet file is missing KDF parameters")
+		}
+		params := *w.KDFParams
+		if params.Time == 0 || params.Threads == 0 || params.KeyLen != keyLen ||
+			params.Memory == 0 || params.Memory > maxArgon2Memory {
+			return KDFParams{}, errors.New("wallet file has invalid KDF parameters")
+		}
+		return params, nil
+	default:
+		return KDFParams{}, fmt.Errorf("unsupported wallet file version: %d", w.Version)
+	}
+}
+
+func (w EncryptedWallet) needsUpgrade() bool {
+	return w.Version != walletVersionCurrent || w.KDFParams == nil || *w.KDFParams != currentKDFParams
+}
+
+func (w EncryptedWallet) additionalData() []byte {
+	if w.Version < walletVersionCurrent || w.KDFParams == nil {
+		return nil
+	}
+	p := w.KDFParams
+	return []byte(fmt.Sprintf("shred-wallet|v%d|%s|%d|%d|%d|%d", w.Version, w.KDF, p.Time, p.Memory, p.Threads, p.KeyLen))
+}
+
+func deriveFileKey(passphrase []byte, salt []byte, params KDFParams) []byte {
+	return argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, params.KeyLen)
 }
 
-func SaveWallet(mnemonic []byte, passphrase string, path string) error {
+func SaveWallet(secret *WalletSecret, passphrase []byte, path string) error {
+	plaintext, err := encodeSecret(secret)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(plaintext)
+
 	salt := make([]byte, saltLen)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		return err
 	}
 
-	key := argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, keyLen)
+	params := currentKDFParams
+	key := deriveFileKey(passphrase, salt, params)
+	defer wipeBytes(key)
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -50,13 +134,14 @@ func SaveWallet(mnemonic []byte, passphrase string, path string) error {
 		return err
 	}
 
-	ciphertext := gcm.Seal(nil, nonce, mnemonic, nil)
-
 	walletData := EncryptedWallet{
-		Salt:       salt,
-		Nonce:      nonce,
-		Ciphertext: ciphertext,
+		Version:   walletVersionCurrent,
+		KDF:       kdfArgon2id,
+		KDFParams: &params,
+		Salt:      salt,
+		Nonce:     nonce,
 	}
+	walletData.Ciphertext = gcm.Seal(nil, nonce, plaintext, walletData.additionalData())
 
 	data, err := json.Marshal(walletData)
 	if err != nil {
@@ -68,10 +153,89 @@ func SaveWallet(mnemonic []byte, passphrase string, path string) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0600)
+	return writeFileAtomic(path, data, 0600)
+}
+
+// ChangePassphrase re-encrypts the wallet at path under newPassphrase. The
+// rewritten file requires signed metadata, so the metadata of an older
+// wallet is signed first and that of a current one must verify; otherwise
+// the wallet is left unchanged.
+func ChangePassphrase(path string, oldPassphrase []byte, newPassphrase []byte) error {
+	secret, err := LoadWallet(oldPassphrase, path)
+	if err != nil {
+		return err
+	}
+	defer secret.Wipe()
+
+	return rewriteWallet(secret, newPassphrase, path)
+}
+
+// UpgradeWallet rewrites a wallet that NeedsUpgrade in the current format
+// and with the current KDF parameters, under the same passphrase. Like
+// ChangePassphrase it signs older metadata first, and leaves the file
+// unchanged when it cannot.
+func UpgradeWallet(secret *WalletSecret, passphrase []byte, path string) error {
+	if err := rewriteWallet(secret, passphrase, path); err != nil {
+		return err
+	}
+	secret.metadataRequired = true
+	secret.needsUpgrade = false
+	return nil
+}
+
+func rewriteWallet(secret *WalletSecret, passphrase []byte, path string) error {
+	var err error
+	if secret.metadataRequired {
+		_, err = LoadVerifiedWalletMetadata(path, secret)
+	} else {
+		err = signLegacyMetadata(path, secret)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot sign the wallet metadata: %w", err)
+	}
+
+	return SaveWallet(secret, passphrase, path)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and
+// renames it over path, so readers see either the old or the new contents.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
 }
 
-func LoadWallet(passphrase string, path string) ([]byte, error) {
+// LoadWallet decrypts the wallet at path without writing to it. The caller
+// owns passphrase and may wipe it once LoadWallet returns.
+func LoadWallet(passphrase []byte, path string) (*WalletSecret, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -82,7 +246,13 @@ func LoadWallet(passphrase string, path string) ([]byte, error) {
 		return nil, err
 	}
 
-	key := argon2.IDKey([]byte(passphrase), walletData.Salt, argon2Time, argon2Memory, argon2Threads, keyLen)
+	params, err := walletData.kdfParams()
+	if err != nil {
+		return nil, err
+	}
+
+	key := deriveFileKey(passphrase, walletData.Salt, params)
+	defer wipeBytes(key)
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -94,10 +264,39 @@ func LoadWallet(passphrase string, path string) ([]byte, error) {
 		return nil, err
 	}
 
-	plaintext, err := gcm.Open(nil, walletData.Nonce, walletData.Ciphertext, nil)
+	plaintext, err := gcm.Open(nil, walletData.Nonce, walletData.Ciphertext, walletData.additionalData())
 	if err != nil {
-		return nil, errors.New("invalid passphrase or corrupted wallet file")
+		return nil, ErrInvalidPassphrase
 	}
+	defer wipeBytes(plaintext)
 
-	return plaintext, nil
+	secret, err := decodeSecret(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	secret.metadataRequired = walletData.Version >= walletVersionCurrent
+	secret.needsUpgrade = walletData.needsUpgrade()
+
+	return secret, nil
+}
+
+// signLegacyMetadata signs metadata written before it was authenticated.
+// Metadata that is already signed must verify. It fails when the wallet has
+// no metadata yet, since the wallet cannot require metadata it lacks.
+func signLegacyMetadata(path string, secret *WalletSecret) error {
+	metadata, err := LoadWalletMetadata(path)
+	if err != nil {
+		return err
+	}
+	if metadata.MAC != "" {
+		_, err := LoadVerifiedWalletMetadata(path, secret)
+		return err
+	}
+	return SaveWalletMetadata(path, metadata, secret)
+}
+
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
 }
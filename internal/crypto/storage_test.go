@@ -0,0 +1,298 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeLegacyWallet writes secret as a version 1 wallet file, which had no
+// version field or additional data, next to unsigned metadata.
+func writeLegacyWallet(t *testing.T, path string, secret *WalletSecret, passphrase []byte, metadata WalletMetadata) {
+	t.Helper()
+
+	plaintext, err := encodeSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := make([]byte, saltLen)
+	rand.Read(salt)
+	block, err := aes.NewCipher(deriveFileKey(passphrase, salt, legacyKDFParams))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+
+	data, err := json.Marshal(EncryptedWallet{
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetMetadataPath(path), meta, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// unlock opens a wallet the way the CLI and the interactive wallet do.
+func unlock(t *testing.T, path string, passphrase []byte) (*WalletSecret, WalletMetadata, error) {
+	t.Helper()
+	secret, err := LoadWallet(passphrase, path)
+	if err != nil {
+		t.Fatalf("LoadWallet: %v", err)
+	}
+	metadata, err := LoadVerifiedWalletMetadata(path, secret)
+	return secret, metadata, err
+}
+
+func TestLegacyWalletUpgradeSignsMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet-test.dat")
+	passphrase := []byte("correct horse battery staple")
+	secret := NewMnemonicSecret([]byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"), nil, KeyTypeECDSA)
+	writeLegacyWallet(t, path, secret, passphrase, WalletMetadata{
+		CreatedAt:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		AccountID:    "0.0.1001",
+		Network:      "mainnet",
+		TokenAliases: map[string]string{"0.0.5": "usd"},
+	})
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metaBefore, err := os.ReadFile(GetMetadataPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unlocking, e.g. from the CLI, only reads the files.
+	secret, metadata, err := unlock(t, path, passphrase)
+	if err != nil {
+		t.Fatalf("first unlock: %v", err)
+	}
+	if metadata.AccountID != "0.0.1001" {
+		t.Fatalf("account ID = %q, want 0.0.1001", metadata.AccountID)
+	}
+	if !secret.NeedsUpgrade() {
+		t.Fatal("a version 1 wallet does not need an upgrade")
+	}
+	after, _ := os.ReadFile(path)
+	metaAfter, _ := os.ReadFile(GetMetadataPath(path))
+	if string(after) != string(before) || string(metaAfter) != string(metaBefore) {
+		t.Fatal("unlocking rewrote the wallet")
+	}
+
+	if err := UpgradeWallet(secret, passphrase, path); err != nil {
+		t.Fatalf("UpgradeWallet: %v", err)
+	}
+	if secret.NeedsUpgrade() {
+		t.Error("the secret still needs an upgrade after UpgradeWallet")
+	}
+
+	var upgraded EncryptedWallet
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &upgraded); err != nil {
+		t.Fatal(err)
+	}
+	if upgraded.Version != walletVersionCurrent {
+		t.Fatalf("wallet version after the upgrade = %d, want %d", upgraded.Version, walletVersionCurrent)
+	}
+
+	// A later unlock, e.g. in the interactive wallet, requires the metadata
+	// to be signed and must keep the user's settings.
+	secret, metadata, err = unlock(t, path, passphrase)
+	if err != nil {
+		t.Fatalf("second unlock: %v", err)
+	}
+	if !secret.metadataRequired || secret.NeedsUpgrade() {
+		t.Errorf("upgraded wallet: metadata required = %v, needs upgrade = %v", secret.metadataRequired, secret.NeedsUpgrade())
+	}
+	if metadata.AccountID != "0.0.1001" || metadata.Network != "mainnet" || metadata.TokenAliases["0.0.5"] != "usd" {
+		t.Errorf("metadata after upgrade = %+v", metadata)
+	}
+}
+
+func TestLegacyWalletWithoutMetadataIsNotUpgraded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet-test.dat")
+	passphrase := []byte("passphrase")
+	secret := NewMnemonicSecret([]byte("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"), nil, KeyTypeECDSA)
+	writeLegacyWallet(t, path, secret, passphrase, WalletMetadata{})
+	if err := os.Remove(GetMetadataPath(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadWallet(passphrase, path)
+	if err != nil {
+		t.Fatalf("LoadWallet: %v", err)
+	}
+	if loaded.metadataRequired {
+		t.Error("a wallet without metadata was upgraded to require it")
+	}
+	if _, err := LoadVerifiedWalletMetadata(path, loaded); errors.Is(err, ErrMetadataTampered) {
+		t.Errorf("missing metadata reported as tampering: %v", err)
+	}
+}
+
+func TestChangePassphrase(t *testing.T) {
+	oldPassphrase, newPassphrase := []byte("old passphrase"), []byte("new passphrase")
+	path := newSignedWallet(t, oldPassphrase)
+
+	if err := ChangePassphrase(path, newPassphrase, oldPassphrase); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Fatalf("ChangePassphrase with the wrong passphrase: err = %v", err)
+	}
+	if err := ChangePassphrase(path, oldPassphrase, newPassphrase); err != nil {
+		t.Fatalf("ChangePassphrase: %v", err)
+	}
+
+	if _, err := LoadWallet(oldPassphrase, path); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("old passphrase after the change: err = %v", err)
+	}
+	_, metadata, err := unlock(t, path, newPassphrase)
+	if err != nil {
+		t.Fatalf("metadata after the change: %v", err)
+	}
+	if metadata.AccountID != "0.0.1001" {
+		t.Errorf("account ID = %q, want 0.0.1001", metadata.AccountID)
+	}
+}
+
+func TestChangePassphraseSignsLegacyMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet-test.dat")
+	oldPassphrase, newPassphrase := []byte("old passphrase"), []byte("new passphrase")
+	secret := NewMnemonicSecret([]byte(testMnemonic12), nil, KeyTypeECDSA)
+	writeLegacyWallet(t, path, secret, oldPassphrase, WalletMetadata{AccountID: "0.0.1001", Network: "mainnet"})
+
+	if err := ChangePassphrase(path, oldPassphrase, newPassphrase); err != nil {
+		t.Fatalf("ChangePassphrase: %v", err)
+	}
+	secret, metadata, err := unlock(t, path, newPassphrase)
+	if err != nil {
+		t.Fatalf("metadata after the change: %v", err)
+	}
+	if !secret.metadataRequired || metadata.AccountID != "0.0.1001" || metadata.Network != "mainnet" {
+		t.Errorf("after the change: metadata required = %v, metadata = %+v", secret.metadataRequired, metadata)
+	}
+}
+
+func TestChangePassphraseKeepsWalletWithBadMetadata(t *testing.T) {
+	oldPassphrase, newPassphrase := []byte("old passphrase"), []byte("new passphrase")
+
+	// A current wallet whose metadata was edited must not have it re-signed.
+	path := newSignedWallet(t, oldPassphrase)
+	editMetadata(t, path, "account_id", "0.0.6666")
+	if err := ChangePassphrase(path, oldPassphrase, newPassphrase); !errors.Is(err, ErrMetadataTampered) {
+		t.Errorf("edited metadata: err = %v, want ErrMetadataTampered", err)
+	}
+	if _, err := LoadWallet(oldPassphrase, path); err != nil {
+		t.Errorf("edited metadata: the wallet was re-encrypted: %v", err)
+	}
+
+	// An old wallet without metadata could not be opened once rewritten.
+	path = filepath.Join(t.TempDir(), "wallet-test.dat")
+	secret := NewMnemonicSecret([]byte(testMnemonic12), nil, KeyTypeECDSA)
+	writeLegacyWallet(t, path, secret, oldPassphrase, WalletMetadata{})
+	if err := os.Remove(GetMetadataPath(path)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ChangePassphrase(path, oldPassphrase, newPassphrase); err == nil {
+		t.Error("missing metadata: ChangePassphrase succeeded")
+	}
+	if _, err := LoadWallet(oldPassphrase, path); err != nil {
+		t.Errorf("missing metadata: the wallet was re-encrypted: %v", err)
+	}
+}
+
+// A failed re-encryption must leave the wallet as it was, openable with the
+// old passphrase, and no partial file behind.
+func TestChangePassphraseFailedWriteKeepsWallet(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	oldPassphrase, newPassphrase := []byte("old passphrase"), []byte("new passphrase")
+	path := newSignedWallet(t, oldPassphrase)
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.Chmod(dir, 0500); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0700)
+
+	if err := ChangePassphrase(path, oldPassphrase, newPassphrase); err == nil {
+		t.Fatal("ChangePassphrase succeeded in a read-only directory")
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("the wallet file changed")
+	}
+	if _, _, err := unlock(t, path, oldPassphrase); err != nil {
+		t.Errorf("old passphrase after the failed change: %v", err)
+	}
+	assertOnlyFiles(t, dir, filepath.Base(path), filepath.Base(GetMetadataPath(path)))
+}
+
+func TestWriteFileAtomicCleansUpOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wallet-test.dat")
+	// Renaming a file over a non-empty directory fails, even for root.
+	if err := os.MkdirAll(filepath.Join(path, "keep"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(path, []byte("new contents"), 0600); err == nil {
+		t.Fatal("writeFileAtomic replaced a directory")
+	}
+	assertOnlyFiles(t, dir, filepath.Base(path))
+}
+
+func assertOnlyFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	if len(got) != len(names) {
+		t.Errorf("%s contains %q, want %q", dir, got, names)
+		return
+	}
+	for i := range names {
+		if got[i] != names[i] {
+			t.Errorf("%s contains %q, want %q", dir, got, names)
+			return
+		}
+	}
+}
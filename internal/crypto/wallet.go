@@ -2,6 +2,8 @@ package crypto
 
 import (
 	"crypto/ed25519"
+	"fmt"
+	"strings"
 
 	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
 	"github.com/tyler-smith/go-bip39"
@@ -46,6 +48,51 @@ func ValidateMnemonic(mnemonic []byte) bool {
 	return bip39.IsMnemonicValid(string(mnemonic))
 }
 
+func IsMnemonicWord(word string) bool {
+	_, ok := bip39.GetWordIndex(word)
+	return ok
+}
+
+func SuggestMnemonicWords(prefix string, limit int) []string {
+	if prefix == "" {
+		return nil
+	}
+
+	var suggestions []string
+	for _, word := range bip39.GetWordList() {
+		if strings.HasPrefix(word, prefix) {
+			suggestions = append(suggestions, word)
+			if len(suggestions) >= limit {
+				break
+			}
+		}
+	}
+	return suggestions
+}
+
+// DerivePrivateKey derives the account key at index using the paths other
+// Hedera wallets use: SLIP-10 m/44'/3030'/0'/0'/index' for ED25519 and
+// BIP-44 m/44'/60'/0'/0/index for ECDSA secp256k1. passphrase is the
+// optional BIP-39 passphrase; each passphrase yields a different wallet.
+func DerivePrivateKey(mnemonic []byte, passphrase string, keyType KeyType, index uint32) (sdk.PrivateKey, error) {
+	hMnemonic, err := sdk.MnemonicFromString(string(mnemonic))
+	if err != nil {
+		return sdk.PrivateKey{}, err
+	}
+
+	switch keyType {
+	case KeyTypeED25519:
+		return hMnemonic.ToStandardEd25519PrivateKey(passphrase, index)
+	case KeyTypeECDSA:
+		return hMnemonic.ToStandardECDSAsecp256k1PrivateKeyCustomDerivationPath(passphrase, fmt.Sprintf("m/44'/60'/0'/0/%d", index))
+	default:
+		return sdk.PrivateKey{}, fmt.Errorf("unknown key type: %s", keyType)
+	}
+}
+
+// DeriveKey and DeriveECDSAKey implement the original shred derivation, which
+// reuses the ED25519 key bytes as an ECDSA key. They are only kept so wallets
+// created before DerivePrivateKey still open to the same account.
 func DeriveKey(mnemonic []byte) (ed25519.PrivateKey, error) {
 	hMnemonic, err := sdk.MnemonicFromString(string(mnemonic))
 	if err != nil {
@@ -69,6 +116,9 @@ func DeriveECDSAKey(mnemonic []byte) (sdk.PrivateKey, error) {
 	return sdk.PrivateKeyFromBytesECDSA(edKeyBytes)
 }
 
-func CalculateEVMAddress(key sdk.PrivateKey) string {
-    return key.PublicKey().ToEvmAddress()
+func CalculateEVMAddress(key sdk.PrivateKey, keyType KeyType) string {
+	if keyType == KeyTypeED25519 {
+		return ""
+	}
+	return key.PublicKey().ToEvmAddress()
 }
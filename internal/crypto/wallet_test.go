@@ -0,0 +1,60 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDerivePrivateKey(t *testing.T) {
+	// The ECDSA keys and addresses match MetaMask and other BIP-44 wallets;
+	// the ED25519 keys were checked against an independent SLIP-10
+	// implementation.
+	tests := []struct {
+		keyType    KeyType
+		index      uint32
+		key        string
+		evmAddress string
+	}{
+		{KeyTypeECDSA, 0, "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727", "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{KeyTypeECDSA, 1, "9a983cb3d832fbde5ab49d692b7a8bf5b5d232479c99333d0fc8e1d21f1b55b6", "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+		{KeyTypeED25519, 0, "523f9ff611ac02e8e188617750e7e50feabd2ef9ee9b218a5c8ce2693275361d", ""},
+		{KeyTypeED25519, 1, "9dfb2c3f4948a104a36ac21e626f412ee120d52befc9bd9b8d8980b4c7d12bb4", ""},
+	}
+
+	for _, tc := range tests {
+		key, err := DerivePrivateKey([]byte(testMnemonic12), "", tc.keyType, tc.index)
+		if err != nil {
+			t.Fatalf("DerivePrivateKey(%s, %d): %v", tc.keyType, tc.index, err)
+		}
+		if got := key.StringRaw(); got != tc.key {
+			t.Errorf("%s key %d = %s, want %s", tc.keyType, tc.index, got, tc.key)
+		}
+		want := strings.ToLower(strings.TrimPrefix(tc.evmAddress, "0x"))
+		if got := CalculateEVMAddress(key, tc.keyType); got != want {
+			t.Errorf("%s address %d = %q, want %q", tc.keyType, tc.index, got, want)
+		}
+	}
+}
+
+func TestDerivePrivateKeyPassphrase(t *testing.T) {
+	for _, keyType := range []KeyType{KeyTypeECDSA, KeyTypeED25519} {
+		plain, err := DerivePrivateKey([]byte(testMnemonic12), "", keyType, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		protected, err := DerivePrivateKey([]byte(testMnemonic12), "TREZOR", keyType, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plain.StringRaw() == protected.StringRaw() {
+			t.Errorf("%s: the passphrase did not change the key", keyType)
+		}
+	}
+}
+
+func TestDerivePrivateKeyRejectsInvalidMnemonic(t *testing.T) {
+	invalid := strings.Replace(testMnemonic12, "about", "abandon", 1)
+	if _, err := DerivePrivateKey([]byte(invalid), "", KeyTypeECDSA, 0); err == nil {
+		t.Error("DerivePrivateKey accepted a phrase with a bad checksum")
+	}
+}
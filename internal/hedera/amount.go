@@ -0,0 +1,84 @@
+package hedera
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// HbarDecimals is the number of tinybar digits in one HBAR.
+const HbarDecimals = 8
+
+// maxDecimals is the most decimal places a Hedera token can have.
+const maxDecimals = 18
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ParseAmount converts a decimal amount such as "12.5" into the smallest unit
+// of an asset with the given number of decimals, without going through
+// floating point. Amounts must be positive, may not have more decimal places
+// than the asset supports and must fit in an int64.
+func ParseAmount(input string, decimals int) (int64, error) {
+	if decimals < 0 || decimals > maxDecimals {
+		return 0, fmt.Errorf("unsupported number of decimals: %d", decimals)
+	}
+
+	input = strings.TrimSpace(input)
+	whole, fraction, _ := strings.Cut(input, ".")
+	if whole == "" && fraction == "" {
+		return 0, ErrInvalidAmount
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, input)
+	}
+
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > decimals {
+		if decimals == 0 {
+			return 0, fmt.Errorf("%w: this asset has no decimal places", ErrInvalidAmount)
+		}
+		return 0, fmt.Errorf("%w: at most %d decimal places are allowed", ErrInvalidAmount, decimals)
+	}
+	fraction += strings.Repeat("0", decimals-len(fraction))
+
+	var units int64
+	for _, digit := range whole + fraction {
+		value := int64(digit - '0')
+		if units > (math.MaxInt64-value)/10 {
+			return 0, fmt.Errorf("%w: amount is too large", ErrInvalidAmount)
+		}
+		units = units*10 + value
+	}
+	if units == 0 {
+		return 0, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+	return units, nil
+}
+
+// FormatAmount renders an amount in smallest units as an exact decimal,
+// dropping trailing zeros.
+func FormatAmount(units int64, decimals int) string {
+	sign := ""
+	magnitude := uint64(units)
+	if units < 0 {
+		sign = "-"
+		magnitude = uint64(-(units + 1)) + 1
+	}
+
+	digits := fmt.Sprintf("%0*d", decimals+1, magnitude)
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
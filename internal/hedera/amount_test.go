@@ -0,0 +1,121 @@
+package hedera
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimals int
+		want     int64
+		invalid  bool // want ErrInvalidAmount
+	}{
+		{input: "1", decimals: 8, want: 100_000_000},
+		{input: "12.5", decimals: 8, want: 1_250_000_000},
+		{input: "0.00000001", decimals: 8, want: 1},
+		{input: "  2.5  ", decimals: 8, want: 250_000_000},
+		{input: "1.100000000", decimals: 8, want: 110_000_000},
+		{input: "007", decimals: 2, want: 700},
+
+		// Assets without decimal places.
+		{input: "5", decimals: 0, want: 5},
+		{input: "5.000", decimals: 0, want: 5},
+		{input: "5.5", decimals: 0, invalid: true},
+
+		// Too many fraction digits.
+		{input: "0.000000001", decimals: 8, invalid: true},
+		{input: "1.005", decimals: 2, invalid: true},
+
+		// Leading and trailing dots.
+		{input: ".5", decimals: 8, want: 50_000_000},
+		{input: "5.", decimals: 8, want: 500_000_000},
+		{input: ".", decimals: 8, invalid: true},
+		{input: "1.2.3", decimals: 8, invalid: true},
+		{input: "..5", decimals: 8, invalid: true},
+
+		// The int64 limit.
+		{input: "9223372036854775807", decimals: 0, want: math.MaxInt64},
+		{input: "9223372036854775808", decimals: 0, invalid: true},
+		{input: "92233720368.54775807", decimals: 8, want: math.MaxInt64},
+		{input: "92233720368.54775808", decimals: 8, invalid: true},
+		{input: "99999999999999999999999999", decimals: 0, invalid: true},
+
+		// Zero, signs and anything that is not a plain decimal.
+		{input: "", decimals: 8, invalid: true},
+		{input: "0", decimals: 8, invalid: true},
+		{input: "0.000", decimals: 8, invalid: true},
+		{input: "-1", decimals: 8, invalid: true},
+		{input: "-0.5", decimals: 8, invalid: true},
+		{input: "+1", decimals: 8, invalid: true},
+		{input: "1e3", decimals: 8, invalid: true},
+		{input: "1E-2", decimals: 8, invalid: true},
+		{input: "1,000", decimals: 8, invalid: true},
+		{input: "1 000", decimals: 8, invalid: true},
+		{input: "0x10", decimals: 8, invalid: true},
+		{input: "١", decimals: 8, invalid: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseAmount(tc.input, tc.decimals)
+		switch {
+		case tc.invalid:
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("ParseAmount(%q, %d) = %d, %v; want ErrInvalidAmount", tc.input, tc.decimals, got, err)
+			}
+		case err != nil:
+			t.Errorf("ParseAmount(%q, %d): %v", tc.input, tc.decimals, err)
+		case got != tc.want:
+			t.Errorf("ParseAmount(%q, %d) = %d, want %d", tc.input, tc.decimals, got, tc.want)
+		}
+	}
+}
+
+func TestParseAmountRejectsUnsupportedDecimals(t *testing.T) {
+	for _, decimals := range []int{-1, maxDecimals + 1} {
+		if _, err := ParseAmount("1", decimals); err == nil {
+			t.Errorf("ParseAmount accepted %d decimals", decimals)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		units    int64
+		decimals int
+		want     string
+	}{
+		{0, 8, "0"},
+		{1, 8, "0.00000001"},
+		{100_000_000, 8, "1"},
+		{150_000_000, 8, "1.5"},
+		{-150_084_000, 8, "-1.50084"},
+		{5, 0, "5"},
+		{-5, 0, "-5"},
+		{1_234_500, 6, "1.2345"},
+		{math.MaxInt64, 0, "9223372036854775807"},
+		{math.MaxInt64, 18, "9.223372036854775807"},
+		{math.MinInt64, 0, "-9223372036854775808"},
+		{math.MinInt64, 8, "-92233720368.54775808"},
+	}
+
+	for _, tc := range tests {
+		if got := FormatAmount(tc.units, tc.decimals); got != tc.want {
+			t.Errorf("FormatAmount(%d, %d) = %q, want %q", tc.units, tc.decimals, got, tc.want)
+		}
+	}
+}
+
+func TestFormatAmountRoundTrips(t *testing.T) {
+	for _, decimals := range []int{0, 2, 6, 8, 18} {
+		for _, units := range []int64{1, 7, 10, 999, 1_000_000, 123_456_789, math.MaxInt64} {
+			formatted := FormatAmount(units, decimals)
+			got, err := ParseAmount(formatted, decimals)
+			if err != nil || got != units {
+				t.Errorf("ParseAmount(FormatAmount(%d, %d) = %q) = %d, %v", units, decimals, formatted, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package hedera
+
+import (
+	"fmt"
+
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// TokenRelationship is a token associated with an account, including ones
+// with a zero balance.
+type TokenRelationship struct {
+	TokenBalance
+	AutomaticAssociation bool
+}
+
+// TokenRelationships lists an account's associated tokens and its automatic
+// association slots. MaxAutoAssociations is -1 when the account has
+// unlimited slots.
+type TokenRelationships struct {
+	Tokens               []TokenRelationship
+	MaxAutoAssociations  int
+	UsedAutoAssociations int
+}
+
+// FreeAutoAssociations returns the open automatic association slots, or -1
+// when they are unlimited.
+func (r TokenRelationships) FreeAutoAssociations() int {
+	if r.MaxAutoAssociations < 0 {
+		return -1
+	}
+	return max(r.MaxAutoAssociations-r.UsedAutoAssociations, 0)
+}
+
+func (c *Client) GetTokenRelationships(accountID string) (TokenRelationships, error) {
+	var account struct {
+		MaxAutoAssociations int `json:"max_automatic_token_associations"`
+	}
+	if err := c.getMirror(fmt.Sprintf("%s/api/v1/accounts/%s", c.MirrorURL, accountID), &account); err != nil {
+		return TokenRelationships{}, err
+	}
+
+	result := TokenRelationships{MaxAutoAssociations: account.MaxAutoAssociations}
+	url := fmt.Sprintf("%s/api/v1/accounts/%s/tokens?limit=100", c.MirrorURL, accountID)
+	for url != "" {
+		var page struct {
+			Tokens []struct {
+				TokenID              string `json:"token_id"`
+				Balance              uint64 `json:"balance"`
+				AutomaticAssociation bool   `json:"automatic_association"`
+			} `json:"tokens"`
+			Links MirrorLinks `json:"links"`
+		}
+		if err := c.getMirror(url, &page); err != nil {
+			return TokenRelationships{}, err
+		}
+
+		for _, token := range page.Tokens {
+			relationship := TokenRelationship{
+				TokenBalance:         TokenBalance{TokenID: token.TokenID, Balance: token.Balance},
+				AutomaticAssociation: token.AutomaticAssociation,
+			}
+			if info, err := c.GetTokenInfo(token.TokenID); err == nil {
+				relationship.Info = &info
+			}
+			if token.AutomaticAssociation {
+				result.UsedAutoAssociations++
+			}
+			result.Tokens = append(result.Tokens, relationship)
+		}
+
+		url = ""
+		if page.Links.Next != "" {
+			url = c.MirrorURL + page.Links.Next
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) AssociateToken(accountID, tokenID string, key sdk.PrivateKey) (string, error) {
+	tx, err := c.NewTokenAssociate(accountID, tokenID)
+	if err != nil {
+		return "", err
+	}
+
+	tx.Sign(key)
+	return c.Submit(tx)
+}
+
+func (c *Client) DissociateToken(accountID, tokenID string, key sdk.PrivateKey) (string, error) {
+	tx, err := c.NewTokenDissociate(accountID, tokenID)
+	if err != nil {
+		return "", err
+	}
+
+	tx.Sign(key)
+	return c.Submit(tx)
+}
+
+// NewTokenAssociate builds and freezes an unsigned association of tokenID
+// with accountID, paid for by the account.
+func (c *Client) NewTokenAssociate(accountID, tokenID string) (*sdk.TokenAssociateTransaction, error) {
+	account, token, err := parseAccountAndToken(accountID, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sdk.NewTokenAssociateTransaction().
+		SetAccountID(account).
+		SetTokenIDs(token).
+		SetTransactionID(sdk.TransactionIDGenerate(account)).
+		SetTransactionMemo(DefaultMemo).
+		SetMaxTransactionFee(c.MaxFee).
+		FreezeWith(c.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// NewTokenDissociate builds and freezes an unsigned dissociation of tokenID
+// from accountID, paid for by the account.
+func (c *Client) NewTokenDissociate(accountID, tokenID string) (*sdk.TokenDissociateTransaction, error) {
+	account, token, err := parseAccountAndToken(accountID, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := sdk.NewTokenDissociateTransaction().
+		SetAccountID(account).
+		SetTokenIDs(token).
+		SetTransactionID(sdk.TransactionIDGenerate(account)).
+		SetTransactionMemo(DefaultMemo).
+		SetMaxTransactionFee(c.MaxFee).
+		FreezeWith(c.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return tx, nil
+}
+
+func parseAccountAndToken(accountID, tokenID string) (sdk.AccountID, sdk.TokenID, error) {
+	account, err := sdk.AccountIDFromString(accountID)
+	if err != nil {
+		return sdk.AccountID{}, sdk.TokenID{}, fmt.Errorf("invalid account ID: %w", err)
+	}
+	token, err := sdk.TokenIDFromString(tokenID)
+	if err != nil {
+		return sdk.AccountID{}, sdk.TokenID{}, fmt.Errorf("invalid token ID: %w", err)
+	}
+	return account, token, nil
+}
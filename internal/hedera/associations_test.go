@@ -0,0 +1,36 @@
+package hedera
+
+import "testing"
+
+// The wallet's client has no operator, so association transactions must
+// carry their own transaction ID to freeze.
+func TestTokenAssociationFreezesWithoutOperator(t *testing.T) {
+	client, err := NewClient(NetworkConfig{Name: NetworkTestnet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	associate, err := client.NewTokenAssociate("0.0.1001", "0.0.5")
+	if err != nil {
+		t.Fatalf("NewTokenAssociate: %v", err)
+	}
+	if got := associate.GetTransactionID().AccountID.String(); got != "0.0.1001" {
+		t.Errorf("associate payer = %s, want 0.0.1001", got)
+	}
+	if got := associate.GetMaxTransactionFee(); got != DefaultMaxFee {
+		t.Errorf("associate max fee = %s, want %s", got, DefaultMaxFee)
+	}
+
+	dissociate, err := client.NewTokenDissociate("0.0.1001", "0.0.5")
+	if err != nil {
+		t.Fatalf("NewTokenDissociate: %v", err)
+	}
+	if got := dissociate.GetTransactionID().AccountID.String(); got != "0.0.1001" {
+		t.Errorf("dissociate payer = %s, want 0.0.1001", got)
+	}
+
+	if _, err := client.NewTokenAssociate("not-an-account", "0.0.5"); err == nil {
+		t.Error("NewTokenAssociate accepted an invalid account ID")
+	}
+}
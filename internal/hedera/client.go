@@ -1,20 +1,123 @@
 package hedera
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
 )
 
+const (
+	NetworkMainnet    = "mainnet"
+	NetworkTestnet    = "testnet"
+	NetworkPreviewnet = "previewnet"
+	NetworkLocal      = "local"
+)
+
+const (
+	DefaultLocalNodeAddress   = "127.0.0.1:50211"
+	DefaultLocalNodeAccountID = "0.0.3"
+	DefaultLocalMirrorURL     = "http://localhost:5551"
+)
+
+var Networks = []string{NetworkMainnet, NetworkTestnet, NetworkPreviewnet, NetworkLocal}
+
+type NetworkConfig struct {
+	Name          string
+	NodeAddress   string
+	NodeAccountID string
+	MirrorURL     string
+}
+
+func DefaultMirrorURL(network string) string {
+	switch network {
+	case NetworkMainnet:
+		return "https://mainnet-public.mirrornode.hedera.com"
+	case NetworkPreviewnet:
+		return "https://previewnet.mirrornode.hedera.com"
+	case NetworkLocal:
+		return DefaultLocalMirrorURL
+	default:
+		return "https://testnet.mirrornode.hedera.com"
+	}
+}
+
+func (n NetworkConfig) DisplayName() string {
+	switch n.Name {
+	case NetworkMainnet:
+		return "Mainnet"
+	case NetworkPreviewnet:
+		return "Previewnet"
+	case NetworkLocal:
+		if n.NodeAddress != "" {
+			return fmt.Sprintf("Local (%s)", n.NodeAddress)
+		}
+		return "Local"
+	default:
+		return "Testnet"
+	}
+}
+
+// Client talks to a Hedera network and its mirror node. MaxFee is set on
+// every transaction the client builds.
 type Client struct {
-	Client *sdk.Client
+	Client    *sdk.Client
+	Network   NetworkConfig
+	MirrorURL string
+	MaxFee    sdk.Hbar
 }
 
-func NewClient() (*Client, error) {
-	client := sdk.ClientForTestnet()
-	return &Client{Client: client}, nil
+func NewClient(network NetworkConfig) (*Client, error) {
+	client, network, err := newSDKClient(network)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorURL := network.MirrorURL
+	if mirrorURL == "" {
+		mirrorURL = DefaultMirrorURL(network.Name)
+	}
+	mirrorURL = strings.TrimRight(mirrorURL, "/")
+
+	return &Client{Client: client, Network: network, MirrorURL: mirrorURL, MaxFee: DefaultMaxFee}, nil
+}
+
+// newSDKClient connects to the network's nodes, filling in the defaults of
+// a local network.
+func newSDKClient(network NetworkConfig) (*sdk.Client, NetworkConfig, error) {
+	if network.Name == "" {
+		network.Name = NetworkTestnet
+	}
+
+	switch network.Name {
+	case NetworkMainnet:
+		return sdk.ClientForMainnet(), network, nil
+	case NetworkTestnet:
+		return sdk.ClientForTestnet(), network, nil
+	case NetworkPreviewnet:
+		return sdk.ClientForPreviewnet(), network, nil
+	case NetworkLocal:
+		if network.NodeAddress == "" {
+			network.NodeAddress = DefaultLocalNodeAddress
+		}
+		if network.NodeAccountID == "" {
+			network.NodeAccountID = DefaultLocalNodeAccountID
+		}
+		nodeID, err := sdk.AccountIDFromString(network.NodeAccountID)
+		if err != nil {
+			return nil, network, fmt.Errorf("invalid node account ID: %w", err)
+		}
+		return sdk.ClientForNetwork(map[string]sdk.AccountID{network.NodeAddress: nodeID}), network, nil
+	default:
+		return nil, network, fmt.Errorf("unknown network: %s", network.Name)
+	}
 }
 
 type AccountInfo struct {
@@ -22,9 +125,21 @@ type AccountInfo struct {
 	Tokens  []TokenBalance
 }
 
+// TokenBalance is a token held by an account, in the token's smallest unit.
+// Info is nil when the token's details could not be looked up.
 type TokenBalance struct {
 	TokenID string
 	Balance uint64
+	Info    *TokenInfo
+}
+
+// FormatBalance renders the balance with the token's decimals, or as raw
+// units when they are unknown.
+func (t TokenBalance) FormatBalance() string {
+	if t.Info == nil {
+		return strconv.FormatUint(t.Balance, 10)
+	}
+	return FormatAmount(int64(t.Balance), t.Info.Decimals)
 }
 
 func (c *Client) GetAccountBalance(accountID sdk.AccountID) (AccountInfo, error) {
@@ -43,6 +158,26 @@ func (c *Client) GetAccountBalance(accountID sdk.AccountID) (AccountInfo, error)
 		})
 	}
 
+	// Most token info is cached; the rest is looked up a few at a time.
+	var wg sync.WaitGroup
+	lookups := make(chan struct{}, tokenInfoLookups)
+	for i := range tokens {
+		wg.Add(1)
+		go func(token *TokenBalance) {
+			defer wg.Done()
+			lookups <- struct{}{}
+			defer func() { <-lookups }()
+
+			if info, err := c.GetTokenInfo(token.TokenID); err == nil {
+				token.Info = &info
+			}
+		}(&tokens[i])
+	}
+	wg.Wait()
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].TokenID < tokens[j].TokenID
+	})
+
 	return AccountInfo{
 		Balance: balance.Hbars,
 		Tokens:  tokens,
@@ -66,12 +201,17 @@ type MirrorAccountDetailResponse struct {
 }
 
 type MirrorTransaction struct {
-	TransactionID      string           `json:"transaction_id"`
-	ConsensusTimestamp string           `json:"consensus_timestamp"`
-	Result             string           `json:"result"`
-	Name               string           `json:"name"`
-	Transfers          []MirrorTransfer `json:"transfers"`
-	MemoBase64         string           `json:"memo_base64"`
+	TransactionID       string                `json:"transaction_id"`
+	ConsensusTimestamp  string                `json:"consensus_timestamp"`
+	ValidStartTimestamp string                `json:"valid_start_timestamp"`
+	Result              string                `json:"result"`
+	Name                string                `json:"name"`
+	Node                string                `json:"node"`
+	ChargedTxFee        int64                 `json:"charged_tx_fee"`
+	Transfers           []MirrorTransfer      `json:"transfers"`
+	TokenTransfers      []MirrorTokenTransfer `json:"token_transfers"`
+	NFTTransfers        []MirrorNFTTransfer   `json:"nft_transfers"`
+	MemoBase64          string                `json:"memo_base64"`
 }
 
 type MirrorTransfer struct {
@@ -79,12 +219,64 @@ type MirrorTransfer struct {
 	Amount  int64  `json:"amount"`
 }
 
+type MirrorTokenTransfer struct {
+	TokenID string `json:"token_id"`
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
+}
+
+type MirrorNFTTransfer struct {
+	TokenID           string `json:"token_id"`
+	SerialNumber      int64  `json:"serial_number"`
+	SenderAccountID   string `json:"sender_account_id"`
+	ReceiverAccountID string `json:"receiver_account_id"`
+}
+
+// Memo decodes the transaction memo. It is empty when there is none or it
+// is not valid base64.
+func (t MirrorTransaction) Memo() string {
+	memo, err := base64.StdEncoding.DecodeString(t.MemoBase64)
+	if err != nil {
+		return ""
+	}
+	return string(memo)
+}
+
+// ParseMirrorTimestamp converts a mirror node timestamp such as
+// "1700000000.123456789" to a time.
+func ParseMirrorTimestamp(timestamp string) (time.Time, error) {
+	seconds, nanos, _ := strings.Cut(timestamp, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	var nsec int64
+	if nanos != "" {
+		nanos = (nanos + "000000000")[:9]
+		if nsec, err = strconv.ParseInt(nanos, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// TransactionURL links to a transaction on HashScan, or is empty for a
+// local network.
+func (n NetworkConfig) TransactionURL(consensusTimestamp string) string {
+	switch n.Name {
+	case NetworkMainnet, NetworkTestnet, NetworkPreviewnet:
+		return fmt.Sprintf("https://hashscan.io/%s/transaction/%s", n.Name, consensusTimestamp)
+	default:
+		return ""
+	}
+}
+
 type MirrorLinks struct {
 	Next string `json:"next"`
 }
 
 func (c *Client) GetAccountIDFromPublicKey(publicKey string) (string, error) {
-	url := fmt.Sprintf("https://testnet.mirrornode.hedera.com/api/v1/accounts?account.publickey=%s", publicKey)
+	url := fmt.Sprintf("%s/api/v1/accounts?account.publickey=%s", c.MirrorURL, publicKey)
 	resp, err := http.Get(url)
 	if err != nil {
 		return "", err
@@ -113,7 +305,7 @@ func (c *Client) GetAccountIDFromEVMAddress(evmAddress string) (string, error) {
 		address = address[2:]
 	}
 	
-	url := fmt.Sprintf("https://testnet.mirrornode.hedera.com/api/v1/accounts/%s", address)
+	url := fmt.Sprintf("%s/api/v1/accounts/%s", c.MirrorURL, address)
 	resp, err := http.Get(url)
 	if err != nil {
 		return "", err
@@ -140,16 +332,34 @@ func (c *Client) GetAccountIDFromEVMAddress(evmAddress string) (string, error) {
 	return "", nil
 }
 
+func (c *Client) GetAccountIDForKey(publicKey sdk.PublicKey, evmAddress string) (string, error) {
+	if evmAddress != "" {
+		accountID, err := c.GetAccountIDFromEVMAddress(evmAddress)
+		if err != nil {
+			return "", err
+		}
+		if accountID != "" {
+			return accountID, nil
+		}
+	}
+
+	return c.GetAccountIDFromPublicKey(publicKey.StringRaw())
+}
+
+// GetAccountInfoWithTransactions fetches an account along with a page of
+// its transactions, newest first. evmAddress can be any form of account ID
+// the mirror node accepts. nextURL, when set, is a mirror node path to fetch
+// instead, such as the Links.Next of an earlier page.
 func (c *Client) GetAccountInfoWithTransactions(evmAddress string, nextURL string) (*MirrorAccountDetailResponse, error) {
 	var url string
 	if nextURL != "" {
-		url = "https://testnet.mirrornode.hedera.com" + nextURL
+		url = c.MirrorURL + nextURL
 	} else {
 		address := evmAddress
 		if len(address) >= 2 && address[0:2] == "0x" {
 			address = address[2:]
 		}
-		url = fmt.Sprintf("https://testnet.mirrornode.hedera.com/api/v1/accounts/%s", address)
+		url = fmt.Sprintf("%s/api/v1/accounts/%s", c.MirrorURL, address)
 	}
 
 	resp, err := http.Get(url)
@@ -170,94 +380,120 @@ func (c *Client) GetAccountInfoWithTransactions(evmAddress string, nextURL strin
 	return &result, nil
 }
 
-func (c *Client) TransferHbar(senderID, recipientID string, amount float64, privateKey string) (string, error) {
-	sender, err := sdk.AccountIDFromString(senderID)
+func (c *Client) TransferHbar(senderID, recipientID string, tinybars int64, memo string, key sdk.PrivateKey) (string, error) {
+	tx, err := c.NewHbarTransfer(senderID, recipientID, tinybars, memo, time.Time{})
 	if err != nil {
-		return "", fmt.Errorf("invalid sender ID: %w", err)
-	}
-
-	recipient, err := sdk.AccountIDFromString(recipientID)
-	if err != nil {
-		return "", fmt.Errorf("invalid recipient ID: %w", err)
+		return "", err
 	}
 
-	key, err := sdk.PrivateKeyFromString(privateKey)
-	if err != nil {
-		return "", fmt.Errorf("invalid private key: %w", err)
-	}
+	tx.Sign(key)
+	return c.Submit(tx)
+}
 
-	txID := sdk.TransactionIDGenerate(sender)
-	tx, err := sdk.NewTransferTransaction().
-		SetTransactionID(txID).
-		AddHbarTransfer(sender, sdk.NewHbar(-amount)).
-		AddHbarTransfer(recipient, sdk.NewHbar(amount)).
-		SetTransactionMemo("Sent via shred").
-		FreezeWith(c.Client)
+func (c *Client) TransferToken(senderID, recipientID, tokenID string, amount int64, memo string, key sdk.PrivateKey) (string, error) {
+	tx, err := c.NewTokenTransfer(senderID, recipientID, tokenID, amount, memo, time.Time{})
 	if err != nil {
-		return "", fmt.Errorf("failed to create transaction: %w", err)
+		return "", err
 	}
 
 	tx.Sign(key)
+	return c.Submit(tx)
+}
 
-	resp, err := tx.Execute(c.Client)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute transaction: %w", err)
-	}
+// MaxValidDuration is the longest a transaction stays valid after its start
+// time. Transactions signed offline should start far enough in the future to
+// be signed and carried back before they expire.
+const MaxValidDuration = 180 * time.Second
 
-	receipt, err := resp.GetReceipt(c.Client)
+// NewHbarTransfer builds and freezes an unsigned transfer of tinybars. The
+// memo is sent exactly as given, with an empty memo meaning none, and a zero
+// validStart starts the transaction now.
+func (c *Client) NewHbarTransfer(senderID, recipientID string, tinybars int64, memo string, validStart time.Time) (*sdk.TransferTransaction, error) {
+	sender, err := sdk.AccountIDFromString(senderID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get receipt: %w", err)
+		return nil, fmt.Errorf("invalid sender ID: %w", err)
 	}
 
-	if receipt.Status != sdk.StatusSuccess {
-		return "", fmt.Errorf("transaction failed with status: %s", receipt.Status)
+	recipient, err := sdk.AccountIDFromString(recipientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient ID: %w", err)
 	}
 
-	return resp.TransactionID.String(), nil
+	tx := sdk.NewTransferTransaction().
+		AddHbarTransfer(sender, sdk.HbarFromTinybar(-tinybars)).
+		AddHbarTransfer(recipient, sdk.HbarFromTinybar(tinybars))
+	return c.freezeTransfer(tx, sender, memo, validStart)
 }
 
-func (c *Client) TransferToken(senderID, recipientID, tokenID string, amount float64, decimals int, privateKey string) (string, error) {
+// NewTokenTransfer builds and freezes an unsigned fungible token transfer of
+// amount in the token's smallest unit. The memo and validStart are handled
+// as in NewHbarTransfer.
+func (c *Client) NewTokenTransfer(senderID, recipientID, tokenID string, amount int64, memo string, validStart time.Time) (*sdk.TransferTransaction, error) {
 	sender, err := sdk.AccountIDFromString(senderID)
 	if err != nil {
-		return "", fmt.Errorf("invalid sender ID: %w", err)
+		return nil, fmt.Errorf("invalid sender ID: %w", err)
 	}
 
 	recipient, err := sdk.AccountIDFromString(recipientID)
 	if err != nil {
-		return "", fmt.Errorf("invalid recipient ID: %w", err)
+		return nil, fmt.Errorf("invalid recipient ID: %w", err)
 	}
 
 	token, err := sdk.TokenIDFromString(tokenID)
 	if err != nil {
-		return "", fmt.Errorf("invalid token ID: %w", err)
+		return nil, fmt.Errorf("invalid token ID: %w", err)
 	}
 
-	key, err := sdk.PrivateKeyFromString(privateKey)
-	if err != nil {
-		return "", fmt.Errorf("invalid private key: %w", err)
+	tx := sdk.NewTransferTransaction().
+		AddTokenTransfer(token, sender, -amount).
+		AddTokenTransfer(token, recipient, amount)
+	return c.freezeTransfer(tx, sender, memo, validStart)
+}
+
+// MaxMemoBytes is the longest transaction memo the network accepts.
+const MaxMemoBytes = 100
+
+// DefaultMemo is sent with the token associations and dissociations the
+// wallet builds. Transfers only carry the memo the user gave.
+const DefaultMemo = "Sent via shred"
+
+// ValidateMemo checks a memo against the network's limits. Exchanges and
+// other custodians use the memo to credit deposits, so it is never trimmed.
+func ValidateMemo(memo string) error {
+	if len(memo) > MaxMemoBytes {
+		return fmt.Errorf("memo is %d bytes; at most %d are allowed", len(memo), MaxMemoBytes)
+	}
+	if strings.ContainsRune(memo, 0) {
+		return fmt.Errorf("memo may not contain a zero byte")
 	}
+	return nil
+}
 
-	rawAmount := int64(amount * float64(1))
-	multiplier := int64(1)
-	for i := 0; i < decimals; i++ {
-		multiplier *= 10
+func (c *Client) freezeTransfer(tx *sdk.TransferTransaction, sender sdk.AccountID, memo string, validStart time.Time) (*sdk.TransferTransaction, error) {
+	if err := ValidateMemo(memo); err != nil {
+		return nil, err
 	}
-	rawAmount = int64(amount * float64(multiplier))
 
 	txID := sdk.TransactionIDGenerate(sender)
-	tx, err := sdk.NewTransferTransaction().
+	if !validStart.IsZero() {
+		txID = sdk.NewTransactionIDWithValidStart(sender, validStart)
+		tx.SetTransactionValidDuration(MaxValidDuration)
+	}
+
+	tx, err := tx.
 		SetTransactionID(txID).
-		AddTokenTransfer(token, sender, -rawAmount).
-		AddTokenTransfer(token, recipient, rawAmount).
-		SetTransactionMemo("Sent via shred").
+		SetTransactionMemo(memo).
+		SetMaxTransactionFee(c.MaxFee).
 		FreezeWith(c.Client)
 	if err != nil {
-		return "", fmt.Errorf("failed to create transaction: %w", err)
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
+	return tx, nil
+}
 
-	tx.Sign(key)
-
-	resp, err := tx.Execute(c.Client)
+// Submit executes a signed transaction and waits for its receipt.
+func (c *Client) Submit(tx sdk.TransactionInterface) (string, error) {
+	resp, err := sdk.TransactionExecute(tx, c.Client)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute transaction: %w", err)
 	}
@@ -274,6 +510,20 @@ func (c *Client) TransferToken(senderID, recipientID, tokenID string, amount flo
 	return resp.TransactionID.String(), nil
 }
 
+// getMirror fetches a mirror node URL and decodes its JSON body into result.
+func (c *Client) getMirror(url string, result any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mirror node returned status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
 func (c *Client) Close() error {
 	return c.Client.Close()
 }
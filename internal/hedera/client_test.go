@@ -0,0 +1,68 @@
+package hedera
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// Exchanges credit deposits by memo, so transfers must carry it byte for
+// byte and add nothing when there is none.
+func TestTransferMemoIsExact(t *testing.T) {
+	client, err := NewClient(NetworkConfig{Name: NetworkTestnet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, memo := range []string{"", "104523", " 104523 ", "\ttag\n", strings.Repeat("é", MaxMemoBytes/2)} {
+		hbar, err := client.NewHbarTransfer("0.0.1001", "0.0.2002", 1, memo, time.Time{})
+		if err != nil {
+			t.Fatalf("NewHbarTransfer(memo %q): %v", memo, err)
+		}
+		if got := hbar.GetTransactionMemo(); got != memo {
+			t.Errorf("HBAR transfer memo = %q, want %q", got, memo)
+		}
+
+		token, err := client.NewTokenTransfer("0.0.1001", "0.0.2002", "0.0.5", 1, memo, time.Time{})
+		if err != nil {
+			t.Fatalf("NewTokenTransfer(memo %q): %v", memo, err)
+		}
+		if got := token.GetTransactionMemo(); got != memo {
+			t.Errorf("token transfer memo = %q, want %q", got, memo)
+		}
+	}
+
+	for _, memo := range []string{strings.Repeat("a", MaxMemoBytes+1), "a\x00b"} {
+		if _, err := client.NewHbarTransfer("0.0.1001", "0.0.2002", 1, memo, time.Time{}); err == nil {
+			t.Errorf("NewHbarTransfer accepted memo %q", memo)
+		}
+	}
+}
+
+// The network charges at most the max fee, so every transfer must carry the
+// wallet's limit rather than the SDK default.
+func TestTransferMaxFee(t *testing.T) {
+	client, err := NewClient(NetworkConfig{Name: NetworkTestnet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.MaxFee = sdk.HbarFromTinybar(12_345_678)
+
+	hbar, err := client.NewHbarTransfer("0.0.1001", "0.0.2002", 1, "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := client.NewTokenTransfer("0.0.1001", "0.0.2002", "0.0.5", 1, "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, fee := range map[string]sdk.Hbar{"HBAR": hbar.GetMaxTransactionFee(), "token": token.GetMaxTransactionFee()} {
+		if fee.AsTinybar() != client.MaxFee.AsTinybar() {
+			t.Errorf("%s transfer max fee = %s, want %s", name, fee, client.MaxFee)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package hedera
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Export formats understood by WriteLedger.
+const (
+	ExportCSV   = "csv"
+	ExportJSONL = "jsonl"
+	ExportOFX   = "ofx"
+)
+
+var ExportFormats = []string{ExportCSV, ExportJSONL, ExportOFX}
+
+// HbarLedgerToken names HBAR in the token column of a ledger.
+const HbarLedgerToken = "HBAR"
+
+// feeAccounts receive the network and node fees of every transaction, so
+// they are not counterparties of the account that paid them.
+var feeAccounts = []string{"0.0.98", "0.0.800", "0.0.801"}
+
+// LedgerEntry is one asset the account sent or received in a transaction.
+// Amount is signed and in display units; an NFT is a Token of the form
+// "serial@token" with an amount of 1 or -1. Fee is the HBAR the account
+// paid for the transaction, recorded on the transaction's first entry only,
+// and Amount never includes it.
+type LedgerEntry struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TransactionID string    `json:"transaction_id"`
+	Type          string    `json:"type"`
+	Counterparty  string    `json:"counterparty"`
+	Token         string    `json:"token"`
+	Amount        string    `json:"amount"`
+	Fee           string    `json:"fee"`
+	Memo          string    `json:"memo"`
+	Result        string    `json:"result"`
+}
+
+// LedgerEntries walks every page of q's account history, ignoring its
+// limit, and returns the account's entries oldest first.
+func (c *Client) LedgerEntries(q HistoryQuery) ([]LedgerEntry, error) {
+	q.Limit = MaxHistoryLimit
+
+	var entries []LedgerEntry
+	for next := accountHistoryPath(q); next != ""; {
+		page, err := c.GetAccountInfoWithTransactions(q.AccountID, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page.Transactions {
+			if !tx.matches(q) {
+				continue
+			}
+			txEntries, err := c.ledgerEntries(q.AccountID, tx)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, txEntries...)
+		}
+		next = page.Links.Next
+	}
+
+	// Pages are newest first; keep each transaction's entries in order.
+	slices.SortStableFunc(entries, func(a, b LedgerEntry) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return entries, nil
+}
+
+func (c *Client) ledgerEntries(accountID string, tx MirrorTransaction) ([]LedgerEntry, error) {
+	timestamp, err := ParseMirrorTimestamp(tx.ConsensusTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	base := LedgerEntry{
+		Timestamp:     timestamp.UTC(),
+		TransactionID: tx.TransactionID,
+		Type:          tx.Name,
+		Memo:          tx.Memo(),
+		Result:        tx.Result,
+	}
+
+	var fee int64
+	if payer, _, _ := strings.Cut(tx.TransactionID, "-"); payer == accountID {
+		fee = tx.ChargedTxFee
+	}
+
+	var entries []LedgerEntry
+	hbar := fee
+	var hbarTransfers []MirrorTokenTransfer
+	for _, transfer := range tx.Transfers {
+		if transfer.Account == accountID {
+			hbar += transfer.Amount
+		}
+		hbarTransfers = append(hbarTransfers, MirrorTokenTransfer{Account: transfer.Account, Amount: transfer.Amount})
+	}
+	if hbar != 0 {
+		excluded := []string{accountID}
+		if hbar < 0 {
+			excluded = append(excluded, tx.Node)
+			excluded = append(excluded, feeAccounts...)
+		}
+		entry := base
+		entry.Token = HbarLedgerToken
+		entry.Amount = FormatAmount(hbar, HbarDecimals)
+		entry.Counterparty = counterparties(hbarTransfers, hbar, excluded)
+		entries = append(entries, entry)
+	}
+
+	byToken := make(map[string][]MirrorTokenTransfer)
+	var tokenIDs []string
+	for _, transfer := range tx.TokenTransfers {
+		if _, ok := byToken[transfer.TokenID]; !ok {
+			tokenIDs = append(tokenIDs, transfer.TokenID)
+		}
+		byToken[transfer.TokenID] = append(byToken[transfer.TokenID], transfer)
+	}
+	for _, tokenID := range tokenIDs {
+		var amount int64
+		for _, transfer := range byToken[tokenID] {
+			if transfer.Account == accountID {
+				amount += transfer.Amount
+			}
+		}
+		if amount == 0 {
+			continue
+		}
+		info, err := c.GetTokenInfo(tokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up token %s: %w", tokenID, err)
+		}
+		entry := base
+		entry.Token = tokenID
+		entry.Amount = FormatAmount(amount, info.Decimals)
+		entry.Counterparty = counterparties(byToken[tokenID], amount, []string{accountID})
+		entries = append(entries, entry)
+	}
+
+	for _, transfer := range tx.NFTTransfers {
+		entry := base
+		entry.Token = fmt.Sprintf("%d@%s", transfer.SerialNumber, transfer.TokenID)
+		switch accountID {
+		case transfer.ReceiverAccountID:
+			entry.Amount = "1"
+			entry.Counterparty = transfer.SenderAccountID
+		case transfer.SenderAccountID:
+			entry.Amount = "-1"
+			entry.Counterparty = transfer.ReceiverAccountID
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	// A transaction that moved nothing still cost its fee.
+	if len(entries) == 0 {
+		entry := base
+		entry.Token = HbarLedgerToken
+		entry.Amount = "0"
+		entries = append(entries, entry)
+	}
+	if fee != 0 {
+		entries[0].Fee = FormatAmount(fee, HbarDecimals)
+	}
+	return entries, nil
+}
+
+// counterparties lists the accounts that moved an asset the other way to
+// amount, leaving out the excluded ones.
+func counterparties(transfers []MirrorTokenTransfer, amount int64, excluded []string) string {
+	var accounts []string
+	for _, transfer := range transfers {
+		if (transfer.Amount < 0) == (amount < 0) || transfer.Amount == 0 {
+			continue
+		}
+		if slices.Contains(excluded, transfer.Account) || slices.Contains(accounts, transfer.Account) {
+			continue
+		}
+		accounts = append(accounts, transfer.Account)
+	}
+	return strings.Join(accounts, ";")
+}
+
+// WriteLedger writes the entries of accountID's ledger in one of the export
+// formats. from and until bound the OFX statement and may be zero, in which
+// case the entries' own range is used.
+func WriteLedger(w io.Writer, format, accountID string, entries []LedgerEntry, from, until time.Time) error {
+	switch format {
+	case ExportCSV:
+		return writeLedgerCSV(w, entries)
+	case ExportJSONL:
+		encoder := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ExportOFX:
+		return writeLedgerOFX(w, accountID, entries, from, until)
+	default:
+		return fmt.Errorf("unknown export format %q: use %s", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+func writeLedgerCSV(w io.Writer, entries []LedgerEntry) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"timestamp", "transaction_id", "type", "counterparty", "token", "amount", "fee", "memo", "result"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Timestamp.Format(time.RFC3339Nano),
+			entry.TransactionID,
+			entry.Type,
+			entry.Counterparty,
+			entry.Token,
+			entry.Amount,
+			entry.Fee,
+			entry.Memo,
+			entry.Result,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeLedgerOFX writes an OFX 2 bank statement. OFX has one currency per
+// statement, so the statement uses XXX (no currency) and each transaction
+// names its asset; fees are separate FEE transactions in HBAR.
+func writeLedgerOFX(w io.Writer, accountID string, entries []LedgerEntry, from, until time.Time) error {
+	if until.IsZero() {
+		until = time.Now()
+		if len(entries) > 0 {
+			until = entries[len(entries)-1].Timestamp
+		}
+	}
+	if from.IsZero() {
+		from = until
+		if len(entries) > 0 {
+			from = entries[0].Timestamp
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n<SIGNONMSGSRSV1><SONRS>\n")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	fmt.Fprintf(&b, "<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE>\n", ofxTime(time.Now()))
+	b.WriteString("</SONRS></SIGNONMSGSRSV1>\n")
+	b.WriteString("<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID>\n")
+	b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	b.WriteString("<STMTRS><CURDEF>XXX</CURDEF>\n")
+	fmt.Fprintf(&b, "<BANKACCTFROM><BANKID>HEDERA</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n", ofxText(accountID))
+	fmt.Fprintf(&b, "<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", ofxTime(from), ofxTime(until))
+
+	for _, entry := range entries {
+		memo := entry.Token
+		if entry.Memo != "" {
+			memo += " " + entry.Memo
+		}
+		if entry.Result != "SUCCESS" {
+			memo += " (" + entry.Result + ")"
+		}
+		trnType := "CREDIT"
+		if strings.HasPrefix(entry.Amount, "-") {
+			trnType = "DEBIT"
+		}
+		// A transaction moves each token once, so the pair identifies the
+		// entry across exports. Entries that only paid a fee have just the
+		// fee transaction.
+		if entry.Amount != "0" {
+			writeOFXTransaction(&b, entry, trnType, entry.TransactionID+"-"+entry.Token, entry.Amount, entry.Counterparty, memo)
+		}
+		if entry.Fee != "" {
+			writeOFXTransaction(&b, entry, "FEE", entry.TransactionID+"-fee", "-"+entry.Fee, "", HbarLedgerToken+" network fee")
+		}
+	}
+
+	b.WriteString("</BANKTRANLIST>\n</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n</OFX>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeOFXTransaction(b *strings.Builder, entry LedgerEntry, trnType, fitID, amount, name, memo string) {
+	b.WriteString("<STMTTRN>")
+	fmt.Fprintf(b, "<TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT>", trnType, ofxTime(entry.Timestamp), amount)
+	fmt.Fprintf(b, "<FITID>%s</FITID>", ofxText(fitID))
+	if name != "" {
+		// OFX limits names to 32 characters.
+		if len(name) > 32 {
+			name = name[:32]
+		}
+		fmt.Fprintf(b, "<NAME>%s</NAME>", ofxText(name))
+	}
+	fmt.Fprintf(b, "<MEMO>%s</MEMO>", ofxText(memo))
+	b.WriteString("</STMTTRN>\n")
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+func ofxText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
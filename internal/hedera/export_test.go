@@ -0,0 +1,372 @@
+package hedera
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testLedger = []LedgerEntry{
+	{
+		Timestamp:     time.Date(2024, 3, 1, 9, 30, 0, 123456789, time.UTC),
+		TransactionID: "0.0.2002-1709285395-000000001",
+		Type:          "CRYPTOTRANSFER",
+		Counterparty:  "0.0.2002",
+		Token:         HbarLedgerToken,
+		Amount:        "12.5",
+		Memo:          `rent, "march"`,
+		Result:        "SUCCESS",
+	},
+	{
+		Timestamp:     time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+		TransactionID: "0.0.1001-1709373595-000000000",
+		Type:          "CRYPTOTRANSFER",
+		Counterparty:  "0.0.3003;0.0.4004",
+		Token:         "0.0.5005",
+		Amount:        "-3.25",
+		Fee:           "0.00184",
+		Memo:          "<tip & thanks>",
+		Result:        "SUCCESS",
+	},
+	{
+		Timestamp:     time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		TransactionID: "0.0.3003-1709424000-000000000",
+		Type:          "CRYPTOTRANSFER",
+		Counterparty:  "0.0.3003",
+		Token:         "7@0.0.6006",
+		Amount:        "1",
+		Result:        "SUCCESS",
+	},
+	{
+		Timestamp:     time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+		TransactionID: "0.0.1001-1709553600-000000000",
+		Type:          "TOKENASSOCIATE",
+		Token:         HbarLedgerToken,
+		Amount:        "0",
+		Fee:           "0.05",
+		Result:        "INSUFFICIENT_PAYER_BALANCE",
+	},
+}
+
+// dtServer matches the OFX server time, which is when the file was written.
+var dtServer = regexp.MustCompile(`<DTSERVER>[^<]*</DTSERVER>`)
+
+func TestWriteLedger(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{ExportCSV, `timestamp,transaction_id,type,counterparty,token,amount,fee,memo,result
+2024-03-01T09:30:00.123456789Z,0.0.2002-1709285395-000000001,CRYPTOTRANSFER,0.0.2002,HBAR,12.5,,"rent, ""march""",SUCCESS
+2024-03-02T10:00:00Z,0.0.1001-1709373595-000000000,CRYPTOTRANSFER,0.0.3003;0.0.4004,0.0.5005,-3.25,0.00184,<tip & thanks>,SUCCESS
+2024-03-03T00:00:00Z,0.0.3003-1709424000-000000000,CRYPTOTRANSFER,0.0.3003,7@0.0.6006,1,,,SUCCESS
+2024-03-04T12:00:00Z,0.0.1001-1709553600-000000000,TOKENASSOCIATE,,HBAR,0,0.05,,INSUFFICIENT_PAYER_BALANCE
+`},
+		{ExportJSONL, `{"timestamp":"2024-03-01T09:30:00.123456789Z","transaction_id":"0.0.2002-1709285395-000000001","type":"CRYPTOTRANSFER","counterparty":"0.0.2002","token":"HBAR","amount":"12.5","fee":"","memo":"rent, \"march\"","result":"SUCCESS"}
+{"timestamp":"2024-03-02T10:00:00Z","transaction_id":"0.0.1001-1709373595-000000000","type":"CRYPTOTRANSFER","counterparty":"0.0.3003;0.0.4004","token":"0.0.5005","amount":"-3.25","fee":"0.00184","memo":"\u003ctip \u0026 thanks\u003e","result":"SUCCESS"}
+{"timestamp":"2024-03-03T00:00:00Z","transaction_id":"0.0.3003-1709424000-000000000","type":"CRYPTOTRANSFER","counterparty":"0.0.3003","token":"7@0.0.6006","amount":"1","fee":"","memo":"","result":"SUCCESS"}
+{"timestamp":"2024-03-04T12:00:00Z","transaction_id":"0.0.1001-1709553600-000000000","type":"TOKENASSOCIATE","counterparty":"","token":"HBAR","amount":"0","fee":"0.05","memo":"","result":"INSUFFICIENT_PAYER_BALANCE"}
+`},
+		{ExportOFX, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS>
+<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<DTSERVER></DTSERVER><LANGUAGE>ENG</LANGUAGE>
+</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID>
+<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>XXX</CURDEF>
+<BANKACCTFROM><BANKID>HEDERA</BANKID><ACCTID>0.0.1001</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>20240301000000.000[0:GMT]</DTSTART><DTEND>20240304120000.000[0:GMT]</DTEND>
+<STMTTRN><TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20240301093000.123[0:GMT]</DTPOSTED><TRNAMT>12.5</TRNAMT><FITID>0.0.2002-1709285395-000000001-HBAR</FITID><NAME>0.0.2002</NAME><MEMO>HBAR rent, &#34;march&#34;</MEMO></STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20240302100000.000[0:GMT]</DTPOSTED><TRNAMT>-3.25</TRNAMT><FITID>0.0.1001-1709373595-000000000-0.0.5005</FITID><NAME>0.0.3003;0.0.4004</NAME><MEMO>0.0.5005 &lt;tip &amp; thanks&gt;</MEMO></STMTTRN>
+<STMTTRN><TRNTYPE>FEE</TRNTYPE><DTPOSTED>20240302100000.000[0:GMT]</DTPOSTED><TRNAMT>-0.00184</TRNAMT><FITID>0.0.1001-1709373595-000000000-fee</FITID><MEMO>HBAR network fee</MEMO></STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20240303000000.000[0:GMT]</DTPOSTED><TRNAMT>1</TRNAMT><FITID>0.0.3003-1709424000-000000000-7@0.0.6006</FITID><NAME>0.0.3003</NAME><MEMO>7@0.0.6006</MEMO></STMTTRN>
+<STMTTRN><TRNTYPE>FEE</TRNTYPE><DTPOSTED>20240304120000.000[0:GMT]</DTPOSTED><TRNAMT>-0.05</TRNAMT><FITID>0.0.1001-1709553600-000000000-fee</FITID><MEMO>HBAR network fee</MEMO></STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`},
+	}
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range tests {
+		var b strings.Builder
+		if err := WriteLedger(&b, tc.format, "0.0.1001", testLedger, from, time.Time{}); err != nil {
+			t.Fatalf("WriteLedger(%s): %v", tc.format, err)
+		}
+		got := dtServer.ReplaceAllString(b.String(), "<DTSERVER></DTSERVER>")
+		if got != tc.want {
+			t.Errorf("WriteLedger(%s) =\n%s\nwant\n%s", tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestWriteLedgerRejectsUnknownFormat(t *testing.T) {
+	var b strings.Builder
+	if err := WriteLedger(&b, "qif", "0.0.1001", testLedger, time.Time{}, time.Time{}); err == nil {
+		t.Error("WriteLedger accepted an unknown format")
+	}
+}
+
+// newTestMirror serves token info for 0.0.5005 (2 decimals) and 0.0.7007
+// (8 decimals), and account pages from accountPages keyed by their query.
+func newTestMirror(t *testing.T, accountPages map[string]string) *Client {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/tokens/0.0.5005":
+			w.Write([]byte(`{"token_id": "0.0.5005", "symbol": "USDT", "decimals": "2", "type": "FUNGIBLE_COMMON"}`))
+		case "/api/v1/tokens/0.0.7007":
+			w.Write([]byte(`{"token_id": "0.0.7007", "symbol": "SAUCE", "decimals": "8", "type": "FUNGIBLE_COMMON"}`))
+		case "/api/v1/accounts/0.0.1001":
+			page, ok := accountPages[r.URL.RawQuery]
+			if !ok {
+				t.Errorf("unexpected account page %q", r.URL.RawQuery)
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(page))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(mirror.Close)
+
+	client, err := NewClient(NetworkConfig{Name: NetworkTestnet, MirrorURL: mirror.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestLedgerEntryAmounts(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   string
+		want []LedgerEntry
+	}{
+		{
+			// The account's HBAR transfer includes the fee it paid.
+			name: "HBAR sent",
+			tx: `{"transaction_id": "0.0.1001-1709285395-000000000", "consensus_timestamp": "1709285400.000000001",
+				"name": "CRYPTOTRANSFER", "result": "SUCCESS", "node": "0.0.3", "charged_tx_fee": 184000,
+				"memo_base64": "cmVudA==",
+				"transfers": [
+					{"account": "0.0.3", "amount": 8000},
+					{"account": "0.0.98", "amount": 176000},
+					{"account": "0.0.1001", "amount": -500184000},
+					{"account": "0.0.2002", "amount": 500000000}
+				]}`,
+			want: []LedgerEntry{{
+				Timestamp:     time.Date(2024, 3, 1, 9, 30, 0, 1, time.UTC),
+				TransactionID: "0.0.1001-1709285395-000000000",
+				Type:          "CRYPTOTRANSFER",
+				Counterparty:  "0.0.2002",
+				Token:         HbarLedgerToken,
+				Amount:        "-5",
+				Fee:           "0.00184",
+				Memo:          "rent",
+				Result:        "SUCCESS",
+			}},
+		},
+		{
+			// Someone else paid the fee, so none is recorded.
+			name: "HBAR received",
+			tx: `{"transaction_id": "0.0.2002-1709285395-000000000", "consensus_timestamp": "1709285400.5",
+				"name": "CRYPTOTRANSFER", "result": "SUCCESS", "node": "0.0.3", "charged_tx_fee": 184000,
+				"transfers": [
+					{"account": "0.0.3", "amount": 8000},
+					{"account": "0.0.98", "amount": 176000},
+					{"account": "0.0.1001", "amount": 1250000000},
+					{"account": "0.0.2002", "amount": -1250184000}
+				]}`,
+			want: []LedgerEntry{{
+				Timestamp:     time.Date(2024, 3, 1, 9, 30, 0, 500000000, time.UTC),
+				TransactionID: "0.0.2002-1709285395-000000000",
+				Type:          "CRYPTOTRANSFER",
+				Counterparty:  "0.0.2002",
+				Token:         HbarLedgerToken,
+				Amount:        "12.5",
+				Result:        "SUCCESS",
+			}},
+		},
+		{
+			// Only the fee moved HBAR, so the token row carries it.
+			name: "token sent to two accounts",
+			tx: `{"transaction_id": "0.0.1001-1709373595-000000000", "consensus_timestamp": "1709373600.000000000",
+				"name": "CRYPTOTRANSFER", "result": "SUCCESS", "node": "0.0.4", "charged_tx_fee": 100000,
+				"transfers": [
+					{"account": "0.0.4", "amount": 5000},
+					{"account": "0.0.98", "amount": 95000},
+					{"account": "0.0.1001", "amount": -100000}
+				],
+				"token_transfers": [
+					{"token_id": "0.0.5005", "account": "0.0.1001", "amount": -325},
+					{"token_id": "0.0.5005", "account": "0.0.3003", "amount": 200},
+					{"token_id": "0.0.5005", "account": "0.0.4004", "amount": 125}
+				]}`,
+			want: []LedgerEntry{{
+				Timestamp:     time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+				TransactionID: "0.0.1001-1709373595-000000000",
+				Type:          "CRYPTOTRANSFER",
+				Counterparty:  "0.0.3003;0.0.4004",
+				Token:         "0.0.5005",
+				Amount:        "-3.25",
+				Fee:           "0.001",
+				Result:        "SUCCESS",
+			}},
+		},
+		{
+			// A swap: HBAR out and a token in, both against the same account.
+			name: "swap",
+			tx: `{"transaction_id": "0.0.1001-1709373595-000000001", "consensus_timestamp": "1709373601.000000000",
+				"name": "CRYPTOTRANSFER", "result": "SUCCESS", "node": "0.0.4", "charged_tx_fee": 200000,
+				"transfers": [
+					{"account": "0.0.4", "amount": 10000},
+					{"account": "0.0.98", "amount": 190000},
+					{"account": "0.0.1001", "amount": -2000200000},
+					{"account": "0.0.8008", "amount": 2000000000}
+				],
+				"token_transfers": [
+					{"token_id": "0.0.7007", "account": "0.0.8008", "amount": -150000000},
+					{"token_id": "0.0.7007", "account": "0.0.1001", "amount": 150000000}
+				]}`,
+			want: []LedgerEntry{
+				{
+					Timestamp:     time.Date(2024, 3, 2, 10, 0, 1, 0, time.UTC),
+					TransactionID: "0.0.1001-1709373595-000000001",
+					Type:          "CRYPTOTRANSFER",
+					Counterparty:  "0.0.8008",
+					Token:         HbarLedgerToken,
+					Amount:        "-20",
+					Fee:           "0.002",
+					Result:        "SUCCESS",
+				},
+				{
+					Timestamp:     time.Date(2024, 3, 2, 10, 0, 1, 0, time.UTC),
+					TransactionID: "0.0.1001-1709373595-000000001",
+					Type:          "CRYPTOTRANSFER",
+					Counterparty:  "0.0.8008",
+					Token:         "0.0.7007",
+					Amount:        "1.5",
+					Result:        "SUCCESS",
+				},
+			},
+		},
+		{
+			name: "NFT received",
+			tx: `{"transaction_id": "0.0.3003-1709424000-000000000", "consensus_timestamp": "1709424000.000000000",
+				"name": "CRYPTOTRANSFER", "result": "SUCCESS", "node": "0.0.3", "charged_tx_fee": 100000,
+				"transfers": [
+					{"account": "0.0.3", "amount": 5000},
+					{"account": "0.0.98", "amount": 95000},
+					{"account": "0.0.3003", "amount": -100000}
+				],
+				"nft_transfers": [
+					{"token_id": "0.0.6006", "serial_number": 7, "sender_account_id": "0.0.3003", "receiver_account_id": "0.0.1001"},
+					{"token_id": "0.0.6006", "serial_number": 8, "sender_account_id": "0.0.3003", "receiver_account_id": "0.0.4004"}
+				]}`,
+			want: []LedgerEntry{{
+				Timestamp:     time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+				TransactionID: "0.0.3003-1709424000-000000000",
+				Type:          "CRYPTOTRANSFER",
+				Counterparty:  "0.0.3003",
+				Token:         "7@0.0.6006",
+				Amount:        "1",
+				Result:        "SUCCESS",
+			}},
+		},
+		{
+			name: "failed association",
+			tx: `{"transaction_id": "0.0.1001-1709553600-000000000", "consensus_timestamp": "1709553600.000000000",
+				"name": "TOKENASSOCIATE", "result": "INSUFFICIENT_PAYER_BALANCE", "node": "0.0.3", "charged_tx_fee": 5000000,
+				"transfers": [
+					{"account": "0.0.3", "amount": 250000},
+					{"account": "0.0.98", "amount": 4750000},
+					{"account": "0.0.1001", "amount": -5000000}
+				]}`,
+			want: []LedgerEntry{{
+				Timestamp:     time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC),
+				TransactionID: "0.0.1001-1709553600-000000000",
+				Type:          "TOKENASSOCIATE",
+				Token:         HbarLedgerToken,
+				Amount:        "0",
+				Fee:           "0.05",
+				Result:        "INSUFFICIENT_PAYER_BALANCE",
+			}},
+		},
+	}
+
+	client := newTestMirror(t, nil)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var tx MirrorTransaction
+			if err := json.Unmarshal([]byte(tc.tx), &tx); err != nil {
+				t.Fatal(err)
+			}
+			got, err := client.ledgerEntries("0.0.1001", tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("entries =\n%+v\nwant\n%+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLedgerEntriesFollowsAccountPages(t *testing.T) {
+	client := newTestMirror(t, map[string]string{
+		"limit=100&order=desc&timestamp=gte%3A1709251200.000000000&transactiontype=CRYPTOTRANSFER": `{
+			"account": "0.0.1001",
+			"transactions": [
+				{"transaction_id": "0.0.2002-1709373595-000000000", "consensus_timestamp": "1709373600.000000000",
+					"name": "CRYPTOTRANSFER", "result": "SUCCESS",
+					"transfers": [{"account": "0.0.2002", "amount": -300000000}, {"account": "0.0.1001", "amount": 300000000}]},
+				{"transaction_id": "0.0.1001-1709373000-000000000", "consensus_timestamp": "1709373005.000000000",
+					"name": "CRYPTOTRANSFER", "result": "INSUFFICIENT_ACCOUNT_BALANCE", "charged_tx_fee": 100000,
+					"transfers": [{"account": "0.0.1001", "amount": -100000}, {"account": "0.0.98", "amount": 100000}]}
+			],
+			"links": {"next": "/api/v1/accounts/0.0.1001?limit=100&order=desc&timestamp=lt:1709373005.000000000"}
+		}`,
+		"limit=100&order=desc&timestamp=lt:1709373005.000000000": `{
+			"account": "0.0.1001",
+			"transactions": [
+				{"transaction_id": "0.0.2002-1709285395-000000000", "consensus_timestamp": "1709285400.000000000",
+					"name": "CRYPTOTRANSFER", "result": "SUCCESS",
+					"transfers": [{"account": "0.0.2002", "amount": -100000000}, {"account": "0.0.1001", "amount": 100000000}]}
+			],
+			"links": {"next": null}
+		}`,
+	})
+
+	entries, err := client.LedgerEntries(HistoryQuery{
+		AccountID: "0.0.1001",
+		Type:      "CRYPTOTRANSFER",
+		Result:    ResultSuccess,
+		Since:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.TransactionID+" "+entry.Amount)
+	}
+	want := []string{"0.0.2002-1709285395-000000000 1", "0.0.2002-1709373595-000000000 3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package hedera
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hiero-ledger/hiero-sdk-go/v2/proto/services"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// Fallback base prices of the transactions the wallet sends, in millionths
+// of a US dollar, copied from the network's published fee schedule. They
+// are only used, and labelled approximate, when the schedule itself cannot
+// be read. The max fee, not these prices, bounds what a transaction costs.
+const (
+	HbarTransferFeeMicroUSD    = 100    // $0.0001
+	TokenTransferFeeMicroUSD   = 1_000  // $0.001
+	NFTTransferFeeMicroUSD     = 1_000  // $0.001
+	TokenAssociateFeeMicroUSD  = 50_000 // $0.05
+	TokenDissociateFeeMicroUSD = 50_000 // $0.05
+)
+
+// DefaultMaxFee caps the fee of every transaction a wallet signs unless the
+// wallet sets its own limit.
+var DefaultMaxFee = sdk.NewHbar(1)
+
+var ErrFeeTooHigh = errors.New("fee exceeds the wallet's max fee")
+
+// FeeKind is a transaction the wallet estimates fees for.
+type FeeKind int
+
+const (
+	FeeHbarTransfer FeeKind = iota
+	FeeTokenTransfer
+	FeeNFTTransfer
+	FeeTokenAssociate
+	FeeTokenDissociate
+)
+
+// TransferFeeKind returns the kind of sending HBAR (tokenID ""), a fungible
+// token or an NFT.
+func TransferFeeKind(tokenID string, nft bool) FeeKind {
+	switch {
+	case nft:
+		return FeeNFTTransfer
+	case tokenID != "":
+		return FeeTokenTransfer
+	default:
+		return FeeHbarTransfer
+	}
+}
+
+// FallbackMicroUSD is the approximate price used when the fee schedule is
+// unavailable.
+func (k FeeKind) FallbackMicroUSD() int64 {
+	switch k {
+	case FeeTokenTransfer:
+		return TokenTransferFeeMicroUSD
+	case FeeNFTTransfer:
+		return NFTTransferFeeMicroUSD
+	case FeeTokenAssociate:
+		return TokenAssociateFeeMicroUSD
+	case FeeTokenDissociate:
+		return TokenDissociateFeeMicroUSD
+	default:
+		return HbarTransferFeeMicroUSD
+	}
+}
+
+// scheduleEntry names the fee schedule's price for the kind: transfers are
+// priced by what they move.
+func (k FeeKind) scheduleEntry() (services.HederaFunctionality, services.SubType) {
+	switch k {
+	case FeeTokenTransfer:
+		return services.HederaFunctionality_CryptoTransfer, services.SubType_TOKEN_FUNGIBLE_COMMON
+	case FeeNFTTransfer:
+		return services.HederaFunctionality_CryptoTransfer, services.SubType_TOKEN_NON_FUNGIBLE_UNIQUE
+	case FeeTokenAssociate:
+		return services.HederaFunctionality_TokenAssociateToAccount, services.SubType_DEFAULT
+	case FeeTokenDissociate:
+		return services.HederaFunctionality_TokenDissociateFromAccount, services.SubType_DEFAULT
+	default:
+		return services.HederaFunctionality_CryptoTransfer, services.SubType_DEFAULT
+	}
+}
+
+// FeeEstimate is the expected fee of a transaction. Approximate is set when
+// the fee schedule could not be used and the price is the kind's fallback;
+// ScheduleError then says why.
+type FeeEstimate struct {
+	Fee           sdk.Hbar
+	MicroUSD      int64
+	Approximate   bool
+	ScheduleError error
+}
+
+// FormatUSD renders millionths of a dollar as a dollar amount, with at least
+// cents.
+func FormatUSD(microUSD int64) string {
+	amount := FormatAmount(microUSD, 6)
+	whole, fraction, _ := strings.Cut(amount, ".")
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	return "$" + whole + "." + fraction
+}
+
+// EstimateFee prices a transaction of the given kind with the cached fee
+// schedule (see LoadFeeSchedule), or its fallback price when there is none,
+// and converts it to HBAR at the mirror node's current exchange rate,
+// rounding up to the next tinybar.
+func (c *Client) EstimateFee(kind FeeKind) (FeeEstimate, error) {
+	var estimate FeeEstimate
+	tinycents, err := c.scheduleFee(kind)
+	if err != nil {
+		estimate.Approximate = true
+		estimate.ScheduleError = err
+		tinycents = kind.FallbackMicroUSD() * tinycentsPerMicroUSD
+	}
+	estimate.MicroUSD = (tinycents + tinycentsPerMicroUSD - 1) / tinycentsPerMicroUSD
+
+	cents, hbars, err := c.exchangeRate()
+	if err != nil {
+		return estimate, err
+	}
+	// A tinycent is 10^-8 cents, as a tinybar is 10^-8 HBAR.
+	numerator := tinycents * hbars
+	estimate.Fee = sdk.HbarFromTinybar((numerator + cents - 1) / cents)
+	return estimate, nil
+}
+
+func (c *Client) scheduleFee(kind FeeKind) (int64, error) {
+	schedule, err := c.currentFeeSchedule(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return scheduleTinycents(schedule, kind)
+}
+
+// exchangeRate returns the network's current rate: hbars HBAR are worth
+// cents US cents.
+func (c *Client) exchangeRate() (cents, hbars int64, err error) {
+	var rate struct {
+		CurrentRate struct {
+			CentEquivalent int64 `json:"cent_equivalent"`
+			HbarEquivalent int64 `json:"hbar_equivalent"`
+		} `json:"current_rate"`
+	}
+	if err := c.getMirror(c.MirrorURL+"/api/v1/network/exchangerate", &rate); err != nil {
+		return 0, 0, err
+	}
+	cents, hbars = rate.CurrentRate.CentEquivalent, rate.CurrentRate.HbarEquivalent
+	if cents <= 0 || hbars <= 0 {
+		return 0, 0, fmt.Errorf("mirror node returned an invalid exchange rate")
+	}
+	return cents, hbars, nil
+}
+
+// CheckFee refuses fees above the client's max fee.
+func (c *Client) CheckFee(fee sdk.Hbar) error {
+	if fee.AsTinybar() > c.MaxFee.AsTinybar() {
+		return fmt.Errorf("%w: %s is more than %s", ErrFeeTooHigh, fee, c.MaxFee)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package hedera
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hiero-ledger/hiero-sdk-go/v2/proto/services"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+	"google.golang.org/protobuf/proto"
+)
+
+// FeeScheduleFileID is the network file holding the current and next fee
+// schedules.
+var FeeScheduleFileID = sdk.FileID{File: 111}
+
+var ErrNoFeeSchedule = errors.New("no current fee schedule")
+
+// The schedule prices resources in thousandths of a tinycent; a tinycent is
+// 10^-8 US cents.
+const (
+	feeDivisorFactor     = 1000
+	tinycentsPerMicroUSD = 10_000
+)
+
+// A transaction the wallet sends with a short memo and one signature. The
+// schedule charges per byte and per signature on top of each constant.
+const (
+	estimatedTransactionBytes = 250
+	estimatedSignatures       = 1
+)
+
+// feeScheduleCache maps a mirror node URL to the serialized fee schedule
+// file of its network. The file only changes when the network updates its
+// prices, and reading it is a paid query, so it is kept on disk.
+type feeScheduleCache struct {
+	mu        sync.Mutex
+	loaded    bool
+	schedules map[string][]byte
+}
+
+var feeSchedules feeScheduleCache
+
+// LoadFeeSchedule reads the fee schedule file, paid for by payerID, unless
+// a schedule that is still in effect is cached. The query costs a small
+// fee and is capped by the client's max fee.
+func (c *Client) LoadFeeSchedule(payerID string, key sdk.PrivateKey) error {
+	if _, err := c.currentFeeSchedule(time.Now()); err == nil {
+		return nil
+	}
+
+	payer, err := sdk.AccountIDFromString(payerID)
+	if err != nil {
+		return fmt.Errorf("invalid payer ID: %w", err)
+	}
+	client, _, err := newSDKClient(c.Network)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	client.SetOperator(payer, key)
+
+	contents, err := sdk.NewFileContentsQuery().
+		SetFileID(FeeScheduleFileID).
+		SetMaxQueryPayment(c.MaxFee).
+		Execute(client)
+	if err != nil {
+		return fmt.Errorf("failed to read the fee schedule: %w", err)
+	}
+	if _, err := selectFeeSchedule(contents, time.Now()); err != nil {
+		return err
+	}
+	feeSchedules.put(c.MirrorURL, contents)
+	return nil
+}
+
+func (c *Client) currentFeeSchedule(now time.Time) (*services.FeeSchedule, error) {
+	contents, ok := feeSchedules.get(c.MirrorURL)
+	if !ok {
+		return nil, ErrNoFeeSchedule
+	}
+	return selectFeeSchedule(contents, now)
+}
+
+// selectFeeSchedule returns whichever of the file's current and next
+// schedules is in effect at now.
+func selectFeeSchedule(contents []byte, now time.Time) (*services.FeeSchedule, error) {
+	var file services.CurrentAndNextFeeSchedule
+	if err := proto.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("invalid fee schedule file: %w", err)
+	}
+	for _, schedule := range []*services.FeeSchedule{file.GetCurrentFeeSchedule(), file.GetNextFeeSchedule()} {
+		if schedule == nil {
+			continue
+		}
+		if expiry := schedule.GetExpiryTime(); expiry == nil || now.Unix() < expiry.GetSeconds() {
+			return schedule, nil
+		}
+	}
+	return nil, ErrNoFeeSchedule
+}
+
+// scheduleTinycents prices one transaction of a kind under schedule.
+func scheduleTinycents(schedule *services.FeeSchedule, kind FeeKind) (int64, error) {
+	functionality, subType := kind.scheduleEntry()
+	for _, entry := range schedule.GetTransactionFeeSchedule() {
+		if entry.GetHederaFunctionality() != functionality {
+			continue
+		}
+		fees := entry.GetFees()
+		if len(fees) == 0 && entry.GetFeeData() != nil {
+			fees = []*services.FeeData{entry.GetFeeData()}
+		}
+		for _, data := range fees {
+			if data.GetSubType() == subType {
+				return componentTinycents(data.GetNodedata()) +
+					componentTinycents(data.GetNetworkdata()) +
+					componentTinycents(data.GetServicedata()), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("the fee schedule has no price for %s", functionality)
+}
+
+// componentTinycents applies one of the node, network and service price
+// components the way the network does: the scaled sum is clamped to the
+// component's bounds before it is divided down to tinycents.
+func componentTinycents(c *services.FeeComponents) int64 {
+	if c == nil {
+		return 0
+	}
+	fee := c.GetConstant() + c.GetBpt()*estimatedTransactionBytes + c.GetVpt()*estimatedSignatures
+	if fee < c.GetMin() {
+		fee = c.GetMin()
+	}
+	if c.GetMax() > 0 && fee > c.GetMax() {
+		fee = c.GetMax()
+	}
+	if fee > 0 && fee < feeDivisorFactor {
+		return 1
+	}
+	return fee / feeDivisorFactor
+}
+
+func feeScheduleCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shred", "feeschedule.json"), nil
+}
+
+func (f *feeScheduleCache) get(mirrorURL string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.load()
+	contents, ok := f.schedules[mirrorURL]
+	return contents, ok
+}
+
+func (f *feeScheduleCache) put(mirrorURL string, contents []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.load()
+	f.schedules[mirrorURL] = contents
+	f.save()
+}
+
+// load reads the cache file once. A missing or unreadable cache only costs
+// another query.
+func (f *feeScheduleCache) load() {
+	if f.loaded {
+		return
+	}
+	f.loaded = true
+	f.schedules = make(map[string][]byte)
+
+	path, err := feeScheduleCachePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	json.Unmarshal(data, &f.schedules)
+	if f.schedules == nil {
+		f.schedules = make(map[string][]byte)
+	}
+}
+
+func (f *feeScheduleCache) save() {
+	path, err := feeScheduleCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(f.schedules)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "feeschedule-*.json")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,173 @@
+package hedera
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hiero-ledger/hiero-sdk-go/v2/proto/services"
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+	"google.golang.org/protobuf/proto"
+)
+
+var testFeeExpiry = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testFeeData(subType services.SubType, service int64) *services.FeeData {
+	return &services.FeeData{
+		SubType:     subType,
+		Nodedata:    &services.FeeComponents{Constant: 100_000_000, Bpt: 1_000, Vpt: 2_000_000, Max: 1_000_000_000_000_000},
+		Networkdata: &services.FeeComponents{Constant: 200_000_000, Bpt: 2_000, Vpt: 4_000_000, Max: 1_000_000_000_000_000},
+		Servicedata: &services.FeeComponents{Constant: service, Max: 1_000_000_000_000_000},
+	}
+}
+
+func testFeeSchedule(expiry time.Time) *services.FeeSchedule {
+	return &services.FeeSchedule{
+		TransactionFeeSchedule: []*services.TransactionFeeSchedule{
+			{
+				HederaFunctionality: services.HederaFunctionality_CryptoTransfer,
+				Fees: []*services.FeeData{
+					testFeeData(services.SubType_DEFAULT, 700_000_000),
+					testFeeData(services.SubType_TOKEN_FUNGIBLE_COMMON, 9_000_000_000),
+				},
+			},
+			{
+				// Older schedules only have the deprecated single entry.
+				HederaFunctionality: services.HederaFunctionality_TokenAssociateToAccount,
+				FeeData: &services.FeeData{
+					Servicedata: &services.FeeComponents{Min: 5_000_000_000_000, Max: 1_000_000_000_000_000},
+				},
+			},
+		},
+		ExpiryTime: &services.TimestampSeconds{Seconds: expiry.Unix()},
+	}
+}
+
+func testFeeScheduleFile(t *testing.T, current, next *services.FeeSchedule) []byte {
+	t.Helper()
+	contents, err := proto.Marshal(&services.CurrentAndNextFeeSchedule{CurrentFeeSchedule: current, NextFeeSchedule: next})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+func TestScheduleTinycents(t *testing.T) {
+	schedule := testFeeSchedule(testFeeExpiry)
+
+	tests := []struct {
+		kind FeeKind
+		want int64
+	}{
+		// Each component is (constant + bpt*250 bytes + vpt*1 signature) / 1000:
+		// node 102,250, network 204,500 and the service constant.
+		{FeeHbarTransfer, 102_250 + 204_500 + 700_000},
+		{FeeTokenTransfer, 102_250 + 204_500 + 9_000_000},
+		// The component minimum applies before dividing.
+		{FeeTokenAssociate, 5_000_000_000},
+	}
+	for _, tc := range tests {
+		got, err := scheduleTinycents(schedule, tc.kind)
+		if err != nil {
+			t.Fatalf("kind %d: %v", tc.kind, err)
+		}
+		if got != tc.want {
+			t.Errorf("kind %d = %d tinycents, want %d", tc.kind, got, tc.want)
+		}
+	}
+
+	for _, kind := range []FeeKind{FeeNFTTransfer, FeeTokenDissociate} {
+		if _, err := scheduleTinycents(schedule, kind); err == nil {
+			t.Errorf("kind %d: priced without a schedule entry", kind)
+		}
+	}
+}
+
+func TestSelectFeeSchedule(t *testing.T) {
+	now := time.Date(2029, 6, 1, 0, 0, 0, 0, time.UTC)
+	current := testFeeSchedule(now.Add(-time.Hour))
+	next := testFeeSchedule(now.Add(time.Hour))
+
+	schedule, err := selectFeeSchedule(testFeeScheduleFile(t, current, next), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.GetExpiryTime().GetSeconds() != next.GetExpiryTime().GetSeconds() {
+		t.Error("an expired current schedule was used instead of the next one")
+	}
+
+	if _, err := selectFeeSchedule(testFeeScheduleFile(t, current, nil), now); !errors.Is(err, ErrNoFeeSchedule) {
+		t.Errorf("expired schedule: err = %v, want ErrNoFeeSchedule", err)
+	}
+	if _, err := selectFeeSchedule([]byte("not a schedule"), now); err == nil {
+		t.Error("accepted an invalid schedule file")
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	// 1 HBAR is worth 12 cents.
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/network/exchangerate" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"current_rate": {"cent_equivalent": 12, "hbar_equivalent": 1}}`))
+	}))
+	defer mirror.Close()
+
+	client, err := NewClient(NetworkConfig{Name: NetworkTestnet, MirrorURL: mirror.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Without a schedule the fallback price of $0.0001 is used.
+	estimate, err := client.EstimateFee(FeeHbarTransfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !estimate.Approximate || !errors.Is(estimate.ScheduleError, ErrNoFeeSchedule) {
+		t.Errorf("without a schedule: approximate = %v, schedule error = %v", estimate.Approximate, estimate.ScheduleError)
+	}
+	if estimate.MicroUSD != HbarTransferFeeMicroUSD || estimate.Fee.AsTinybar() != 83_334 {
+		t.Errorf("fallback estimate = %d micro-USD, %d tinybars; want 100, 83334", estimate.MicroUSD, estimate.Fee.AsTinybar())
+	}
+
+	feeSchedules.put(client.MirrorURL, testFeeScheduleFile(t, testFeeSchedule(testFeeExpiry), nil))
+	if err := client.LoadFeeSchedule("not an account", sdk.PrivateKey{}); err != nil {
+		t.Errorf("LoadFeeSchedule queried the network with a schedule cached: %v", err)
+	}
+
+	tests := []struct {
+		kind        FeeKind
+		microUSD    int64
+		tinybars    int64
+		approximate bool
+	}{
+		{FeeHbarTransfer, 101, 83_896, false},
+		{FeeTokenTransfer, 931, 775_563, false},
+		{FeeTokenAssociate, 500_000, 416_666_667, false},
+		// The schedule has no NFT price, so the fallback applies.
+		{FeeNFTTransfer, NFTTransferFeeMicroUSD, 833_334, true},
+	}
+	for _, tc := range tests {
+		estimate, err := client.EstimateFee(tc.kind)
+		if err != nil {
+			t.Fatalf("kind %d: %v", tc.kind, err)
+		}
+		if estimate.MicroUSD != tc.microUSD || estimate.Fee.AsTinybar() != tc.tinybars || estimate.Approximate != tc.approximate {
+			t.Errorf("kind %d = %d micro-USD, %d tinybars, approximate %v; want %d, %d, %v",
+				tc.kind, estimate.MicroUSD, estimate.Fee.AsTinybar(), estimate.Approximate, tc.microUSD, tc.tinybars, tc.approximate)
+		}
+	}
+
+	client.MaxFee = sdk.HbarFromTinybar(400_000_000)
+	if err := client.CheckFee(sdk.HbarFromTinybar(416_666_667)); !errors.Is(err, ErrFeeTooHigh) {
+		t.Errorf("CheckFee above the max fee: err = %v", err)
+	}
+}
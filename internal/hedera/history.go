@@ -0,0 +1,270 @@
+package hedera
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values of HistoryQuery.Result and HistoryQuery.Direction, as the mirror
+// node names them.
+const (
+	ResultSuccess = "success"
+	ResultFail    = "fail"
+	DirectionIn   = "credit"
+	DirectionOut  = "debit"
+)
+
+const (
+	DefaultHistoryLimit = 25
+	MaxHistoryLimit     = 100
+)
+
+// HistoryTypes are the transaction types a wallet usually filters on.
+var HistoryTypes = []string{
+	"CRYPTOTRANSFER",
+	"TOKENASSOCIATE",
+	"TOKENDISSOCIATE",
+	"CRYPTOCREATEACCOUNT",
+	"CRYPTOUPDATEACCOUNT",
+	"CRYPTOAPPROVEALLOWANCE",
+	"CONTRACTCALL",
+	"TOKENMINT",
+}
+
+// HistoryQuery selects an account's transactions, newest first. Zero fields
+// do not filter, and Until is exclusive.
+type HistoryQuery struct {
+	AccountID string
+	Limit     int
+	Type      string
+	Result    string
+	Direction string
+	TokenID   string
+	Since     time.Time
+	Until     time.Time
+}
+
+// TransactionPage is one page of history. Next is the mirror node path of
+// the following page, or empty on the last one.
+type TransactionPage struct {
+	Transactions []MirrorTransaction
+	Next         string
+}
+
+// maxTokenFilterPages bounds the mirror node pages GetTransactions reads to
+// fill one page of a token filter, so a token that rarely moves cannot walk
+// the account's whole history in one call.
+const maxTokenFilterPages = 10
+
+// HistoryURL builds the mirror node request for the first page of q. The
+// mirror node cannot filter by token, so a token filter reads full pages
+// and GetTransactions drops the transactions that do not match.
+func (c *Client) HistoryURL(q HistoryQuery) string {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	if q.TokenID != "" {
+		limit = MaxHistoryLimit
+	}
+
+	params := url.Values{}
+	params.Set("account.id", q.AccountID)
+	params.Set("limit", strconv.Itoa(min(limit, MaxHistoryLimit)))
+	params.Set("order", "desc")
+	if q.Type != "" {
+		params.Set("transactiontype", q.Type)
+	}
+	if q.Result != "" {
+		params.Set("result", q.Result)
+	}
+	if q.Direction != "" {
+		params.Set("type", q.Direction)
+	}
+	if !q.Since.IsZero() {
+		params.Add("timestamp", "gte:"+mirrorTimestamp(q.Since))
+	}
+	if !q.Until.IsZero() {
+		params.Add("timestamp", "lt:"+mirrorTimestamp(q.Until))
+	}
+	return c.MirrorURL + "/api/v1/transactions?" + params.Encode()
+}
+
+// accountHistoryPath is the mirror node path of the first page of q on the
+// account endpoint, which GetAccountInfoWithTransactions pages through. That
+// endpoint only filters by type and time; matches applies the rest.
+func accountHistoryPath(q HistoryQuery) string {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(min(limit, MaxHistoryLimit)))
+	params.Set("order", "desc")
+	if q.Type != "" {
+		params.Set("transactiontype", q.Type)
+	}
+	if !q.Since.IsZero() {
+		params.Add("timestamp", "gte:"+mirrorTimestamp(q.Since))
+	}
+	if !q.Until.IsZero() {
+		params.Add("timestamp", "lt:"+mirrorTimestamp(q.Until))
+	}
+	return "/api/v1/accounts/" + url.PathEscape(q.AccountID) + "?" + params.Encode()
+}
+
+// GetTransactions fetches the first page of q, or the page at pageURL (a
+// Next path from an earlier page). TokenID is applied here, since the mirror
+// node cannot filter by token: further mirror pages are read until the page
+// holds q.Limit transactions, and Next continues after the last one kept. A
+// page can still come back short, or empty with a Next, after
+// maxTokenFilterPages mirror pages.
+func (c *Client) GetTransactions(q HistoryQuery, pageURL string) (TransactionPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	limit = min(limit, MaxHistoryLimit)
+
+	requestURL := c.HistoryURL(q)
+	if pageURL != "" {
+		requestURL = c.MirrorURL + pageURL
+	}
+
+	var page TransactionPage
+	for pages := 1; ; pages++ {
+		var result struct {
+			Transactions []MirrorTransaction `json:"transactions"`
+			Links        MirrorLinks         `json:"links"`
+		}
+		if err := c.getMirror(requestURL, &result); err != nil {
+			return TransactionPage{}, err
+		}
+
+		page.Next = result.Links.Next
+		for i, tx := range result.Transactions {
+			if q.TokenID != "" && !tx.movesToken(q.TokenID) {
+				continue
+			}
+			page.Transactions = append(page.Transactions, tx)
+			if len(page.Transactions) < limit {
+				continue
+			}
+			if i < len(result.Transactions)-1 {
+				next, err := c.historyPathBefore(q, tx)
+				if err != nil {
+					return TransactionPage{}, err
+				}
+				page.Next = next
+			}
+			return page, nil
+		}
+
+		if q.TokenID == "" || page.Next == "" || pages == maxTokenFilterPages {
+			return page, nil
+		}
+		requestURL = c.MirrorURL + page.Next
+	}
+}
+
+// historyPathBefore is the mirror node path of q's transactions older than
+// tx.
+func (c *Client) historyPathBefore(q HistoryQuery, tx MirrorTransaction) (string, error) {
+	timestamp, err := ParseMirrorTimestamp(tx.ConsensusTimestamp)
+	if err != nil {
+		return "", err
+	}
+	q.Until = timestamp
+	return strings.TrimPrefix(c.HistoryURL(q), c.MirrorURL), nil
+}
+
+// matches applies the result, direction and token filters of q. As on the
+// mirror node, the direction only looks at the account's HBAR transfers.
+func (t MirrorTransaction) matches(q HistoryQuery) bool {
+	switch q.Result {
+	case ResultSuccess:
+		if t.Result != "SUCCESS" {
+			return false
+		}
+	case ResultFail:
+		if t.Result == "SUCCESS" {
+			return false
+		}
+	}
+	if q.Direction != "" {
+		moved := false
+		for _, transfer := range t.Transfers {
+			if transfer.Account != q.AccountID {
+				continue
+			}
+			if (q.Direction == DirectionIn && transfer.Amount > 0) || (q.Direction == DirectionOut && transfer.Amount < 0) {
+				moved = true
+			}
+		}
+		if !moved {
+			return false
+		}
+	}
+	return q.TokenID == "" || t.movesToken(q.TokenID)
+}
+
+func (t MirrorTransaction) movesToken(tokenID string) bool {
+	for _, transfer := range t.TokenTransfers {
+		if transfer.TokenID == tokenID {
+			return true
+		}
+	}
+	for _, transfer := range t.NFTTransfers {
+		if transfer.TokenID == tokenID {
+			return true
+		}
+	}
+	return false
+}
+
+func mirrorTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+// ParseHistoryRange reads the bounds of a history query. Each may be empty,
+// an RFC 3339 time or a local date (2006-01-02); a date as the end of the
+// range includes the whole day.
+func ParseHistoryRange(since, until string) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if since != "" {
+		t, _, err := parseHistoryTime(since)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = t
+	}
+	if until != "" {
+		t, isDate, err := parseHistoryTime(until)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if isDate {
+			t = t.AddDate(0, 0, 1)
+		}
+		end = t
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the start of the range must be before its end")
+	}
+	return start, end, nil
+}
+
+func parseHistoryTime(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q: use YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, false, nil
+}
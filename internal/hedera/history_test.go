@@ -0,0 +1,158 @@
+package hedera
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryURL(t *testing.T) {
+	client := &Client{MirrorURL: "https://mirror.example"}
+	since := time.Unix(1709251200, 0)
+	until := time.Unix(1711929600, 5)
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		want  string
+	}{
+		{
+			name:  "defaults",
+			query: HistoryQuery{AccountID: "0.0.1001"},
+			want:  "account.id=0.0.1001&limit=25&order=desc",
+		},
+		{
+			name:  "limit above the mirror node's",
+			query: HistoryQuery{AccountID: "0.0.1001", Limit: 500},
+			want:  "account.id=0.0.1001&limit=100&order=desc",
+		},
+		{
+			name: "mirror filters",
+			query: HistoryQuery{
+				AccountID: "0.0.1001",
+				Limit:     10,
+				Type:      "CRYPTOTRANSFER",
+				Result:    ResultFail,
+				Direction: DirectionOut,
+				Since:     since,
+				Until:     until,
+			},
+			want: "account.id=0.0.1001&limit=10&order=desc&result=fail" +
+				"&timestamp=gte%3A1709251200.000000000&timestamp=lt%3A1711929600.000000005" +
+				"&transactiontype=CRYPTOTRANSFER&type=debit",
+		},
+		{
+			// The token is filtered after loading, so whole pages are read.
+			name:  "token",
+			query: HistoryQuery{AccountID: "0.0.1001", Limit: 10, TokenID: "0.0.5005"},
+			want:  "account.id=0.0.1001&limit=100&order=desc",
+		},
+	}
+
+	for _, tc := range tests {
+		want := "https://mirror.example/api/v1/transactions?" + tc.want
+		if got := client.HistoryURL(tc.query); got != want {
+			t.Errorf("%s: HistoryURL =\n%s\nwant\n%s", tc.name, got, want)
+		}
+	}
+}
+
+// testHistoryTx is a transfer at second, which moves tokenID unless it is
+// empty.
+func testHistoryTx(second int, tokenID string) string {
+	tokenTransfers := "[]"
+	if tokenID != "" {
+		tokenTransfers = fmt.Sprintf(`[{"token_id": %q, "account": "0.0.1001", "amount": 1}]`, tokenID)
+	}
+	return fmt.Sprintf(`{"transaction_id": "0.0.1001-%d-000000000", "consensus_timestamp": "%d.000000000", "token_transfers": %s}`,
+		second, second, tokenTransfers)
+}
+
+func testHistoryPage(next string, txs ...string) string {
+	if next != "" {
+		next = fmt.Sprintf("%q", next)
+	} else {
+		next = "null"
+	}
+	return fmt.Sprintf(`{"transactions": [%s], "links": {"next": %s}}`, strings.Join(txs, ","), next)
+}
+
+func TestGetTransactionsFillsTokenPages(t *testing.T) {
+	pages := map[string]string{
+		"account.id=0.0.1001&limit=100&order=desc": testHistoryPage("/api/v1/transactions?page=2",
+			testHistoryTx(1709285409, "0.0.5005"),
+			testHistoryTx(1709285408, ""),
+			testHistoryTx(1709285407, "0.0.5005")),
+		"page=2": testHistoryPage("/api/v1/transactions?page=3",
+			testHistoryTx(1709285406, ""),
+			testHistoryTx(1709285405, "0.0.5005"),
+			testHistoryTx(1709285404, "0.0.5005")),
+		"account.id=0.0.1001&limit=100&order=desc&timestamp=lt%3A1709285405.000000000": testHistoryPage("",
+			testHistoryTx(1709285404, "0.0.5005"),
+			testHistoryTx(1709285403, "0.0.6006")),
+	}
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.RawQuery]
+		if r.URL.Path != "/api/v1/transactions" || !ok {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer mirror.Close()
+	client := &Client{MirrorURL: mirror.URL}
+
+	query := HistoryQuery{AccountID: "0.0.1001", Limit: 3, TokenID: "0.0.5005"}
+	var got [][]string
+	next := ""
+	for {
+		page, err := client.GetTransactions(query, next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, tx := range page.Transactions {
+			ids = append(ids, tx.ConsensusTimestamp)
+		}
+		got = append(got, ids)
+		if page.Next == "" {
+			break
+		}
+		next = page.Next
+	}
+
+	want := [][]string{
+		{"1709285409.000000000", "1709285407.000000000", "1709285405.000000000"},
+		{"1709285404.000000000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %q, want %q", got, want)
+	}
+}
+
+func TestGetTransactionsBoundsTokenSearch(t *testing.T) {
+	requests := 0
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		next := fmt.Sprintf("/api/v1/transactions?page=%d", requests+1)
+		w.Write([]byte(testHistoryPage(next, testHistoryTx(1709285400-requests, ""))))
+	}))
+	defer mirror.Close()
+	client := &Client{MirrorURL: mirror.URL}
+
+	page, err := client.GetTransactions(HistoryQuery{AccountID: "0.0.1001", TokenID: "0.0.5005"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != maxTokenFilterPages {
+		t.Errorf("read %d mirror pages, want %d", requests, maxTokenFilterPages)
+	}
+	if len(page.Transactions) != 0 || page.Next != "/api/v1/transactions?page=11" {
+		t.Errorf("page = %d transactions, next %q", len(page.Transactions), page.Next)
+	}
+}
@@ -0,0 +1,177 @@
+package hedera
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sdk "github.com/hiero-ledger/hiero-sdk-go/v2/sdk"
+)
+
+// TokenTypeNFT is the mirror node's type for non-fungible token collections.
+const TokenTypeNFT = "NON_FUNGIBLE_UNIQUE"
+
+// IsNFT reports whether the token is a non-fungible collection.
+func (t TokenInfo) IsNFT() bool {
+	return t.Type == TokenTypeNFT
+}
+
+// NFT is a single serial of a non-fungible token owned by an account.
+// Collection is nil when the token's details could not be looked up.
+type NFT struct {
+	TokenID      string
+	SerialNumber int64
+	Metadata     []byte
+	Collection   *TokenInfo
+}
+
+// NFTMetadata is the HIP-412 metadata document of an NFT.
+type NFTMetadata struct {
+	Name        string         `json:"name"`
+	Creator     string         `json:"creator"`
+	Description string         `json:"description"`
+	Image       string         `json:"image"`
+	Type        string         `json:"type"`
+	Attributes  []NFTAttribute `json:"attributes"`
+	Properties  map[string]any `json:"properties"`
+}
+
+type NFTAttribute struct {
+	TraitType string `json:"trait_type"`
+	Value     any    `json:"value"`
+}
+
+// DecodeMetadata reads the NFT's on-chain metadata. HIP-412 JSON stored
+// inline or as a data URI is returned as a document. Anything else, usually
+// an IPFS or HTTPS link to the document, is returned as text; the link is
+// not followed. Both are empty when the metadata is not readable text.
+func (n NFT) DecodeMetadata() (*NFTMetadata, string) {
+	text := strings.TrimSpace(string(n.Metadata))
+	if text == "" || !utf8.ValidString(text) {
+		return nil, ""
+	}
+
+	document := []byte(text)
+	if strings.HasPrefix(text, "data:") {
+		mediaType, data, err := decodeDataURI(text)
+		if err != nil || (mediaType != "application/json" && !json.Valid(data)) {
+			// Show the kind of data rather than a page of base64.
+			return nil, "data:" + mediaType
+		}
+		document = data
+	}
+
+	var metadata NFTMetadata
+	if err := json.Unmarshal(document, &metadata); err != nil {
+		return nil, text
+	}
+	return &metadata, ""
+}
+
+// decodeDataURI splits an RFC 2397 data URI into its media type and data.
+func decodeDataURI(uri string) (string, []byte, error) {
+	header, data, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return "", nil, fmt.Errorf("malformed data URI")
+	}
+
+	encoded := strings.HasSuffix(header, ";base64")
+	header = strings.TrimSuffix(header, ";base64")
+	mediaType := "text/plain"
+	if header != "" {
+		if parsed, _, err := mime.ParseMediaType(header); err == nil {
+			mediaType = parsed
+		}
+	}
+
+	if encoded {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return mediaType, nil, err
+		}
+		return mediaType, decoded, nil
+	}
+	decoded, err := url.PathUnescape(data)
+	if err != nil {
+		return mediaType, nil, err
+	}
+	return mediaType, []byte(decoded), nil
+}
+
+func (c *Client) GetNFTs(accountID string) ([]NFT, error) {
+	var nfts []NFT
+	url := fmt.Sprintf("%s/api/v1/accounts/%s/nfts?limit=100", c.MirrorURL, accountID)
+	for url != "" {
+		var page struct {
+			NFTs []struct {
+				TokenID      string `json:"token_id"`
+				SerialNumber int64  `json:"serial_number"`
+				Metadata     []byte `json:"metadata"`
+				Deleted      bool   `json:"deleted"`
+			} `json:"nfts"`
+			Links MirrorLinks `json:"links"`
+		}
+		if err := c.getMirror(url, &page); err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.NFTs {
+			if item.Deleted {
+				continue
+			}
+			nft := NFT{
+				TokenID:      item.TokenID,
+				SerialNumber: item.SerialNumber,
+				Metadata:     item.Metadata,
+			}
+			if info, err := c.GetTokenInfo(item.TokenID); err == nil {
+				nft.Collection = &info
+			}
+			nfts = append(nfts, nft)
+		}
+
+		url = ""
+		if page.Links.Next != "" {
+			url = c.MirrorURL + page.Links.Next
+		}
+	}
+	return nfts, nil
+}
+
+func (c *Client) TransferNFT(senderID, recipientID, tokenID string, serial int64, memo string, key sdk.PrivateKey) (string, error) {
+	tx, err := c.NewNFTTransfer(senderID, recipientID, tokenID, serial, memo, time.Time{})
+	if err != nil {
+		return "", err
+	}
+
+	tx.Sign(key)
+	return c.Submit(tx)
+}
+
+// NewNFTTransfer builds and freezes an unsigned transfer of one NFT serial.
+// The memo and validStart are handled as in NewHbarTransfer.
+func (c *Client) NewNFTTransfer(senderID, recipientID, tokenID string, serial int64, memo string, validStart time.Time) (*sdk.TransferTransaction, error) {
+	sender, err := sdk.AccountIDFromString(senderID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender ID: %w", err)
+	}
+
+	recipient, err := sdk.AccountIDFromString(recipientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient ID: %w", err)
+	}
+
+	token, err := sdk.TokenIDFromString(tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token ID: %w", err)
+	}
+
+	nftID := sdk.NftID{TokenID: token, SerialNumber: serial}
+	tx := sdk.NewTransferTransaction().AddNftTransfer(nftID, sender, recipient)
+	return c.freezeTransfer(tx, sender, memo, validStart)
+}
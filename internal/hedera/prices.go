@@ -0,0 +1,156 @@
+package hedera
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCurrency is the fiat currency balances are valued in unless the
+// config chooses another.
+const DefaultCurrency = "USD"
+
+// HbarPriceKey names HBAR in price files, where other assets are token IDs.
+const HbarPriceKey = "HBAR"
+
+var ErrNoPrice = errors.New("no price available")
+
+// PriceProvider values assets in a fiat currency. A price is for one whole
+// unit: one HBAR, or one token with its decimals applied. tokenID is empty
+// for HBAR. Providers return ErrNoPrice for assets or currencies they do
+// not cover.
+type PriceProvider interface {
+	Price(tokenID, currency string) (*big.Rat, error)
+}
+
+// PriceChain asks each provider in turn and returns the first price found.
+// A provider that fails does not hide the ones after it; its error is only
+// returned when no other provider has a price.
+type PriceChain []PriceProvider
+
+func (p PriceChain) Price(tokenID, currency string) (*big.Rat, error) {
+	failure := ErrNoPrice
+	for _, provider := range p {
+		price, err := provider.Price(tokenID, currency)
+		if err == nil {
+			return price, nil
+		}
+		if !errors.Is(err, ErrNoPrice) && errors.Is(failure, ErrNoPrice) {
+			failure = err
+		}
+	}
+	return nil, failure
+}
+
+// MirrorPrices prices HBAR in USD from the mirror node's exchange rate. The
+// network updates the rate hourly, so it is fetched at most once a minute.
+type MirrorPrices struct {
+	Client *Client
+
+	mu      sync.Mutex
+	price   *big.Rat
+	fetched time.Time
+}
+
+func (p *MirrorPrices) Price(tokenID, currency string) (*big.Rat, error) {
+	if tokenID != "" || !strings.EqualFold(currency, "USD") {
+		return nil, ErrNoPrice
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.price != nil && time.Since(p.fetched) < time.Minute {
+		return p.price, nil
+	}
+
+	cents, hbars, err := p.Client.exchangeRate()
+	if err != nil {
+		return nil, err
+	}
+	p.price = big.NewRat(cents, hbars*100)
+	p.fetched = time.Now()
+	return p.price, nil
+}
+
+// StaticPrices maps a currency code to asset prices, keyed by token ID or
+// HbarPriceKey and written as decimal strings, e.g.
+//
+//	{"USD": {"HBAR": "0.095", "0.0.456858": "1.00"}}
+type StaticPrices map[string]map[string]string
+
+func (p StaticPrices) Price(tokenID, currency string) (*big.Rat, error) {
+	if tokenID == "" {
+		tokenID = HbarPriceKey
+	}
+	value, ok := p[strings.ToUpper(currency)][tokenID]
+	if !ok {
+		return nil, ErrNoPrice
+	}
+	price, ok := new(big.Rat).SetString(value)
+	if !ok || price.Sign() < 0 {
+		return nil, fmt.Errorf("invalid price %q for %s", value, tokenID)
+	}
+	return price, nil
+}
+
+// FilePrices reads StaticPrices from a JSON file on every lookup, so the
+// file can be updated while the wallet runs.
+type FilePrices struct {
+	Path string
+}
+
+func (p FilePrices) Price(tokenID, currency string) (*big.Rat, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prices: %w", err)
+	}
+	var prices StaticPrices
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", p.Path, err)
+	}
+	return prices.Price(tokenID, currency)
+}
+
+// NewPriceProvider prices assets from the price file, when there is one,
+// and falls back to the mirror node for HBAR.
+func NewPriceProvider(client *Client, priceFile string) PriceProvider {
+	var chain PriceChain
+	if priceFile != "" {
+		chain = append(chain, FilePrices{Path: priceFile})
+	}
+	if client != nil {
+		chain = append(chain, &MirrorPrices{Client: client})
+	}
+	return chain
+}
+
+// FiatValue is what units of an asset with the given decimals are worth at
+// price.
+func FiatValue(units int64, decimals int, price *big.Rat) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value := new(big.Rat).SetFrac(big.NewInt(units), scale)
+	return value.Mul(value, price)
+}
+
+// FormatFiat renders a fiat value rounded to cents, e.g. "$12.34" or
+// "12.34 EUR".
+func FormatFiat(value *big.Rat, currency string) string {
+	amount := value.FloatString(2)
+	if strings.Trim(amount, "-0.") == "" {
+		amount = strings.TrimPrefix(amount, "-")
+	}
+	switch strings.ToUpper(currency) {
+	case "USD":
+		if strings.HasPrefix(amount, "-") {
+			return "-$" + amount[1:]
+		}
+		return "$" + amount
+	default:
+		return amount + " " + strings.ToUpper(currency)
+	}
+}
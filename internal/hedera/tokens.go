@@ -0,0 +1,172 @@
+package hedera
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// TokenInfo is the part of a token's mirror node record the wallet needs to
+// show and send it. Decimals never change after a token is created, so
+// lookups are cached on disk.
+type TokenInfo struct {
+	TokenID  string `json:"token_id"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	Type     string `json:"type"`
+}
+
+// tokenCache maps a mirror node URL to the tokens looked up on it, since the
+// same token ID means different tokens on different networks.
+type tokenCache struct {
+	mu     sync.Mutex
+	loaded bool
+	tokens map[string]map[string]TokenInfo
+}
+
+var tokens tokenCache
+
+// tokenInfoLookups bounds the mirror node requests made at once when
+// looking up an account's tokens.
+const tokenInfoLookups = 8
+
+// GetTokenInfo returns a token's name, symbol and decimals, asking the
+// mirror node only when the token is not cached yet.
+func (c *Client) GetTokenInfo(tokenID string) (TokenInfo, error) {
+	if info, ok := tokens.get(c.MirrorURL, tokenID); ok {
+		return info, nil
+	}
+
+	info, err := c.fetchTokenInfo(tokenID)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	tokens.put(c.MirrorURL, info)
+	return info, nil
+}
+
+func (c *Client) fetchTokenInfo(tokenID string) (TokenInfo, error) {
+	url := fmt.Sprintf("%s/api/v1/tokens/%s", c.MirrorURL, tokenID)
+	resp, err := http.Get(url)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return TokenInfo{}, fmt.Errorf("token %s not found", tokenID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return TokenInfo{}, fmt.Errorf("mirror node returned status: %d", resp.StatusCode)
+	}
+
+	// The mirror node returns decimals as a string.
+	var result struct {
+		TokenID  string `json:"token_id"`
+		Name     string `json:"name"`
+		Symbol   string `json:"symbol"`
+		Decimals string `json:"decimals"`
+		Type     string `json:"type"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return TokenInfo{}, err
+	}
+
+	decimals, err := strconv.Atoi(result.Decimals)
+	if err != nil || decimals < 0 || decimals > maxDecimals {
+		return TokenInfo{}, fmt.Errorf("token %s has invalid decimals %q", tokenID, result.Decimals)
+	}
+
+	return TokenInfo{
+		TokenID:  result.TokenID,
+		Name:     result.Name,
+		Symbol:   result.Symbol,
+		Decimals: decimals,
+		Type:     result.Type,
+	}, nil
+}
+
+func tokenCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "shred", "tokens.json"), nil
+}
+
+func (t *tokenCache) get(mirrorURL, tokenID string) (TokenInfo, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.load()
+	info, ok := t.tokens[mirrorURL][tokenID]
+	return info, ok
+}
+
+func (t *tokenCache) put(mirrorURL string, info TokenInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.load()
+	if t.tokens[mirrorURL] == nil {
+		t.tokens[mirrorURL] = make(map[string]TokenInfo)
+	}
+	t.tokens[mirrorURL][info.TokenID] = info
+	t.save()
+}
+
+// load reads the cache file once. A missing or unreadable cache only costs
+// extra lookups.
+func (t *tokenCache) load() {
+	if t.loaded {
+		return
+	}
+	t.loaded = true
+	t.tokens = make(map[string]map[string]TokenInfo)
+
+	path, err := tokenCachePath()
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	json.Unmarshal(data, &t.tokens)
+	if t.tokens == nil {
+		t.tokens = make(map[string]map[string]TokenInfo)
+	}
+}
+
+func (t *tokenCache) save() {
+	path, err := tokenCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(t.tokens, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "tokens-*.json")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}